@@ -7,6 +7,9 @@ type Address struct {
 	PostalCode string `json:"postal_code,omitempty"`
 	City       string `json:"city,omitempty"`
 	Country    string `json:"country,omitempty"`
+
+	// CountryCode is the ISO 3166-1 alpha-2 code of country, used by e-invoice exports
+	CountryCode string `json:"country_code,omitempty"`
 }
 
 // ToString output address as string
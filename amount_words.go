@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/shopspring/decimal"
+)
+
+var englishUnits = []string{
+	"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+	"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen",
+	"seventeen", "eighteen", "nineteen",
+}
+
+var englishTens = []string{
+	"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety",
+}
+
+var englishScales = []string{"", "thousand", "million", "billion", "trillion", "quadrillion", "quintillion"}
+
+// AmountInWords return amount spelled out as written on checks, ex "One thousand
+// two hundred thirty-four euros and 56 cents". Currency is an ISO 4217 code,
+// units names being left out when unknown and cents written as a fraction over
+// 100, ex "and 56/100". Only english is supported, other languages falling back to it.
+func AmountInWords(d decimal.Decimal, lang string, currency string) string {
+	d = d.Round(2)
+
+	sign := ""
+	if d.IsNegative() {
+		sign = "minus "
+		d = d.Abs()
+	}
+
+	integer := d.Truncate(0)
+	cents := d.Sub(integer).Mul(decimal.New(100, 0)).IntPart()
+
+	words := sign + englishNumber(uint64(integer.IntPart()))
+	if units, ok := CurrencyUnits(currency); ok {
+		major := units.MajorPlural
+		if integer.Equal(decimal.New(1, 0)) {
+			major = units.Major
+		}
+
+		minor := units.MinorPlural
+		if cents == 1 {
+			minor = units.Minor
+		}
+
+		words = fmt.Sprintf("%s %s and %d %s", words, major, cents, minor)
+	} else {
+		words = fmt.Sprintf("%s and %02d/100", words, cents)
+	}
+
+	return strings.ToUpper(words[:1]) + words[1:]
+}
+
+// englishNumber return number spelled out in english, ex two hundred thirty-four
+func englishNumber(number uint64) string {
+	if number == 0 {
+		return englishUnits[0]
+	}
+
+	var groups []string
+	for scale := 0; number > 0; scale++ {
+		if group := number % 1000; group > 0 {
+			words := englishHundreds(group)
+			if len(englishScales[scale]) > 0 {
+				words += " " + englishScales[scale]
+			}
+			groups = append([]string{words}, groups...)
+		}
+		number /= 1000
+	}
+
+	return strings.Join(groups, " ")
+}
+
+// englishHundreds return number below 1000 spelled out in english
+func englishHundreds(number uint64) string {
+	var words []string
+	if number >= 100 {
+		words = append(words, englishUnits[number/100]+" hundred")
+		number %= 100
+	}
+
+	switch {
+	case number >= 20 && number%10 > 0:
+		words = append(words, englishTens[number/10]+"-"+englishUnits[number%10])
+	case number >= 20:
+		words = append(words, englishTens[number/10])
+	case number > 0:
+		words = append(words, englishUnits[number])
+	}
+
+	return strings.Join(words, " ")
+}
+
+// showsAmountInWords return true if amount due is spelled out below totals,
+// proformas having no amount due
+func (d *Document) showsAmountInWords() bool {
+	return d.Options.ShowAmountInWords && d.Type != Proforma
+}
+
+// appendAmountInWords draw amount due spelled out below totals block
+func (d *Document) appendAmountInWords(pdf *gofpdf.Fpdf) {
+	if !d.showsAmountInWords() {
+		return
+	}
+
+	y := pdf.GetY() + 11
+	if d.Options.PricesIncludeTax && len(d.foreignCurrencies()) == 0 {
+		y += 5
+	}
+
+	words := AmountInWords(d.computeTotals().amountDue.Abs(), "en", d.Options.CurrencyCode)
+
+	pdf.SetXY(d.endX(80), y)
+	pdf.SetFont(d.Options.fontFamily(), "", BaseTextFontSize)
+	pdf.MultiCell(80, 4, d.Options.encodeString(words), "0", d.Options.align("R"), false)
+	pdf.SetY(pdf.GetY() - 10)
+}
@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Attachment represent a file attached to the document
+type Attachment struct {
+	Filename    string `json:"filename,omitempty" validate:"required"`
+	Description string `json:"description,omitempty"`
+	Content     []byte `json:"content,omitempty"`
+}
+
+// validateAttachments check attachments do not exceed Options.MaxAttachmentSize
+func (d *Document) validateAttachments() error {
+	for index, attachment := range d.Attachments {
+		if d.Options.MaxAttachmentSize > 0 && len(attachment.Content) > d.Options.MaxAttachmentSize {
+			return &FieldError{Field: fmt.Sprintf("Attachments[%d]", index), Err: ErrAttachmentTooLarge}
+		}
+	}
+
+	return nil
+}
+
+// pdfAttachments return document attachments as embedded files
+func (d *Document) pdfAttachments() []gofpdf.Attachment {
+	attachments := make([]gofpdf.Attachment, 0, len(d.Attachments))
+	for _, attachment := range d.Attachments {
+		attachments = append(attachments, gofpdf.Attachment{
+			Content:     attachment.Content,
+			Filename:    attachment.Filename,
+			Description: attachment.Description,
+		})
+	}
+
+	return attachments
+}
+
+func (d *Document) appendAttachmentsList(pdf *gofpdf.Fpdf) {
+	if !d.Options.ShowAttachmentsList || len(d.Attachments) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(d.Attachments))
+	for _, attachment := range d.Attachments {
+		names = append(names, attachment.Filename)
+	}
+
+	listString := fmt.Sprintf("%s: %s", d.Options.TextAttachmentsTitle, strings.Join(names, ", "))
+
+	// List is rendered just above footer of the last page
+	listY := d.maxPageHeight() + 5
+	if pdf.GetY()+5 > listY {
+		pdf.AddPage()
+	}
+
+	pdf.SetXY(d.Options.MarginLeft, listY)
+	pdf.SetFont(d.Options.fontFamily(), "", SmallTextFontSize)
+	pdf.SetTextColor(d.Options.Theme.GreyTextColor[0], d.Options.Theme.GreyTextColor[1], d.Options.Theme.GreyTextColor[2])
+	pdf.MultiCell(d.contentWidth(), 3, d.Options.encodeString(listString), "0", "L", false)
+
+	// Reset font
+	pdf.SetFont(d.Options.fontFamily(), "", BaseTextFontSize)
+	pdf.SetTextColor(d.Options.Theme.BaseTextColor[0], d.Options.Theme.BaseTextColor[1], d.Options.Theme.BaseTextColor[2])
+}
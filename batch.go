@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"context"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// BuildBatch build documents in a single pdf, each document starting on a new
+// page with its own header, footer and totals. Pages are numbered across the
+// whole batch, page size and orientation being those of the first document.
+// Attachments of all documents are embedded, xmp metadata and auto print are
+// not applied. Errors are returned as *DocumentError with failing document index.
+func BuildBatch(docs []*Document) (*gofpdf.Fpdf, error) {
+	return BuildBatchContext(context.Background(), docs)
+}
+
+// BuildBatchContext build documents in a single pdf like BuildBatch, aborting
+// with ctx error, wrapped in *DocumentError, when ctx is done before all
+// documents are drawn
+func BuildBatchContext(ctx context.Context, docs []*Document) (*gofpdf.Fpdf, error) {
+	if len(docs) == 0 {
+		return nil, ErrNoDocuments
+	}
+
+	// Validate all documents before drawing any
+	for index, doc := range docs {
+		if err := doc.Validate(); err != nil {
+			return nil, &DocumentError{Index: index, Err: err}
+		}
+	}
+
+	pdf := gofpdf.New(docs[0].Options.Orientation, "mm", docs[0].Options.PageSize, "")
+
+	var attachments []gofpdf.Attachment
+	for index, doc := range docs {
+		if err := ctx.Err(); err != nil {
+			return nil, &DocumentError{Index: index, Err: err}
+		}
+
+		if _, err := doc.appendTo(ctx, pdf); err != nil {
+			return nil, &DocumentError{Index: index, Err: err}
+		}
+
+		if pdf.Err() {
+			return nil, &DocumentError{Index: index, Err: pdf.Error()}
+		}
+
+		attachments = append(attachments, doc.pdfAttachments()...)
+	}
+
+	if len(attachments) > 0 {
+		pdf.SetAttachments(attachments)
+	}
+
+	return pdf, nil
+}
@@ -2,406 +2,729 @@ package generator
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"time"
+	"image"
+	"math"
+	"strings"
 
 	"github.com/jung-kurt/gofpdf"
-	"github.com/leekchan/accounting"
 	"github.com/shopspring/decimal"
 )
 
 // Build pdf document from data provided
 func (d *Document) Build() (*gofpdf.Fpdf, error) {
+	return d.BuildContext(context.Background())
+}
+
+// BuildContext build pdf document like Build, aborting with ctx error when ctx
+// is done before document is fully drawn
+func (d *Document) BuildContext(ctx context.Context) (*gofpdf.Fpdf, error) {
+	pdf, _, err := d.buildWithLayout(ctx)
+	return pdf, err
+}
+
+// BuildWithLayout build pdf document and report where generated content is,
+// so callers can append their own content
+func (d *Document) BuildWithLayout() (*gofpdf.Fpdf, *Layout, error) {
+	return d.buildWithLayout(context.Background())
+}
+
+func (d *Document) buildWithLayout(ctx context.Context) (*gofpdf.Fpdf, *Layout, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
 	// Validate document data
 	err := d.Validate()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Build base doc
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.SetMargins(BaseMargin, BaseMarginTop, BaseMargin)
-	pdf.SetXY(10, 10)
-	pdf.SetTextColor(BaseTextColor[0], BaseTextColor[1], BaseTextColor[2])
+	pdf := gofpdf.New(d.Options.Orientation, "mm", d.Options.PageSize, "")
 
-	// Set header
-	if d.Header != nil {
-		err = d.Header.applyHeader(d, pdf)
+	layout, err := d.appendTo(ctx, pdf)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Embed attachments
+	if len(d.Attachments) > 0 {
+		pdf.SetAttachments(d.pdfAttachments())
+	}
+
+	// Embed document data as xmp metadata if XMPMetadata == true
+	if d.Options.XMPMetadata {
+		pdf.SetXmpMetadata(d.xmpMetadata())
+	}
+
+	// Append js to autoprint if AutoPrint == true
+	if d.Options.AutoPrint {
+		pdf.SetJavascript("print(true);")
+	}
+
+	return pdf, layout, nil
+}
 
-		if err != nil {
+// appendTo draw document on pdf, starting on a new page with its own header
+// and footer, and report where generated content is. Drawing stops with ctx
+// error between steps once ctx is done.
+func (d *Document) appendTo(ctx context.Context, pdf *gofpdf.Fpdf) (*Layout, error) {
+	pdf.SetMargins(d.Options.MarginLeft, d.marginTop(), d.Options.MarginRight)
+	pdf.SetXY(d.Options.MarginLeft, d.marginTop())
+	pdf.SetTextColor(d.Options.Theme.BaseTextColor[0], d.Options.Theme.BaseTextColor[1], d.Options.Theme.BaseTextColor[2])
+
+	// Register custom font
+	if d.Options.Font != nil {
+		d.Options.Font.register(pdf)
+	}
+
+	if pdf.Err() {
+		return nil, pdf.Error()
+	}
+
+	// Set header, replacing previous document one
+	if d.Header != nil {
+		if err := d.Header.applyHeader(d, pdf); err != nil {
 			return nil, err
 		}
+	} else if d.Options.LogoOnEveryPage || len(d.Options.Watermark) > 0 {
+		pdf.SetHeaderFunc(func() {
+			d.appendWatermark(pdf)
+			d.appendRepeatedLogo(pdf)
+		})
+	} else {
+		pdf.SetHeaderFunc(nil)
 	}
 
+	// Add first page, previous document last page footer being drawn
+	pdf.AddPage()
+
 	// Set footer
 	if d.Footer != nil {
-		err = d.Footer.applyFooter(d, pdf)
-
-		if err != nil {
+		if err := d.Footer.applyFooter(d, pdf); err != nil {
 			return nil, err
 		}
+	} else if d.Options.ShowPageNumbers {
+		pdf.SetFooterFunc(func() {
+			d.appendPageNumber(pdf)
+		})
+	} else {
+		pdf.SetFooterFunc(nil)
 	}
 
-	// Add first page
-	pdf.AddPage()
-
 	// Load font
-	pdf.SetFont("Helvetica", "", 12)
+	pdf.SetFont(d.Options.fontFamily(), "", 12)
 
 	// Appenf document title
 	d.appendTitle(pdf)
 
 	// Appenf document metas (ref & version)
-	d.appendMetas(pdf)
+	metasBottom := d.appendMetas(pdf)
+
+	// Contact drawn on metas side starts below metas, customer unless parties are swapped
+	metasSideTop := d.marginTop() + 25
+	if metasBottom+2 > metasSideTop {
+		metasSideTop = metasBottom + 2
+	}
+
+	companyTop, customerTop := d.marginTop(), metasSideTop
+	if d.Options.SwapPartyColumns {
+		companyTop, customerTop = metasSideTop, d.marginTop()
+	}
 
 	// Append company contact to doc
-	companyBottom := d.Company.appendCompanyContactToDoc(pdf)
+	companyBottom := d.Company.appendCompanyContactToDoc(d.Options, companyTop, pdf)
 
 	// Append customer contact to doc
-	customerBottom := d.Customer.appendCustomerContactToDoc(pdf)
+	customerBottom := d.Customer.appendCustomerContactToDoc(d.Options, customerTop, pdf)
+
+	// Append ship to contact below customer
+	if d.ShipTo != nil {
+		customerBottom = d.ShipTo.appendShipToContactToDoc(d.Options, customerBottom+3, pdf)
+	}
 
 	if customerBottom > companyBottom {
-		pdf.SetXY(10, customerBottom)
+		pdf.SetXY(d.Options.MarginLeft, customerBottom)
 	} else {
-		pdf.SetXY(10, companyBottom)
+		pdf.SetXY(d.Options.MarginLeft, companyBottom)
 	}
 
 	// Append description
 	d.appendDescription(pdf)
 
 	// Append items
-	d.appendItems(pdf)
+	if err := d.appendItems(ctx, pdf); err != nil {
+		return nil, err
+	}
+
+	// Append notes between items and totals
+	d.appendNotesAboveTotals(pdf)
 
 	// Check page height (total bloc height = 30, 45 when doc discount)
 	offset := pdf.GetY() + 30
 	if d.Discount != nil {
 		offset += 15
 	}
-	if offset > MaxPageHeight {
+	if d.Shipping != nil {
+		offset += 10
+	}
+	if rows := len(d.amountDueRows(&documentTotals{})); rows > 0 {
+		offset += float64(rows) * 10
+	}
+	if d.showsAmountInWords() {
+		offset += 10
+	}
+	offset += d.taxSummaryHeight()
+	if offset > d.maxPageHeight() {
 		pdf.AddPage()
 	}
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Append notes
 	d.appendNotes(pdf)
 
+	// Append per tax rate summary
+	d.appendTaxSummary(pdf)
+
 	// Append total
-	d.appendTotal(pdf)
+	layout := &Layout{TotalsPage: pdf.PageNo(), ContentTop: d.marginTop()}
+	layout.Totals = d.appendTotal(pdf)
+
+	// Append proforma disclaimer
+	d.appendProformaDisclaimer(pdf)
 
 	// Append payment term
 	d.appendPaymentTerm(pdf)
 
-	// Append js to autoprint if AutoPrint == true
-	if d.Options.AutoPrint {
-		pdf.SetJavascript("print(true);")
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Append remit to address
+	d.appendRemitTo(pdf)
+
+	// Append signature block
+	d.appendSignature(pdf)
+
+	// Append how to pay panel
+	d.appendPaymentPanel(pdf)
+
+	// Append payment qr code, when not in how to pay panel
+	d.appendStandalonePaymentQR(pdf)
+
+	// Append notes after totals (flow or separate page modes)
+	d.appendNotesAfterTotals(pdf)
+
+	// Append footer message at the bottom of last page
+	d.appendFooterMessage(pdf)
+
+	// List attachments if ShowAttachmentsList == true
+	d.appendAttachmentsList(pdf)
+
+	// Add blank page for duplex printing
+	d.appendBlankPage(pdf)
+
+	layout.FinalY = pdf.GetY()
+	layout.PageCount = pdf.PageCount()
+
+	return layout, nil
+}
+
+// appendBlankPage add a blank page when document ends on an odd page and
+// PadToEvenPages is set, so next document starts on the front of a sheet
+func (d *Document) appendBlankPage(pdf *gofpdf.Fpdf) {
+	if !d.Options.PadToEvenPages || pdf.PageNo()%2 == 0 {
+		return
 	}
 
-	return pdf, nil
+	pdf.AddPage()
+
+	if len(d.Options.TextBlankPage) > 0 {
+		pdf.SetXY(d.Options.MarginLeft, d.maxPageHeight()/2)
+		pdf.SetFont(d.Options.fontFamily(), "", LargeTextFontSize)
+		pdf.SetTextColor(d.Options.Theme.GreyTextColor[0], d.Options.Theme.GreyTextColor[1], d.Options.Theme.GreyTextColor[2])
+		pdf.CellFormat(d.contentWidth(), 10, d.Options.encodeString(d.Options.TextBlankPage), "0", 0, "C", false, 0, "")
+		pdf.SetTextColor(d.Options.Theme.BaseTextColor[0], d.Options.Theme.BaseTextColor[1], d.Options.Theme.BaseTextColor[2])
+	}
 }
 
 func (d *Document) appendTitle(pdf *gofpdf.Fpdf) {
-	title := d.typeAsString()
+	title := d.Options.encodeString(d.typeAsString())
+	pdf.SetFont(d.Options.fontFamily(), "", 14)
+
+	// Grow box to fit title, within page margins
+	width := math.Max(d.Options.TitleBoxWidth, pdf.GetStringWidth(title)+10)
+	width = math.Min(width, d.contentWidth())
 
 	// Set x y
-	pdf.SetXY(120, BaseMarginTop)
+	pdf.SetXY(d.endX(width), d.marginTop())
 
-	// Draw rect
-	pdf.SetFillColor(DarkBgColor[0], DarkBgColor[1], DarkBgColor[2])
-	pdf.Rect(120, BaseMarginTop, 80, 10, "F")
+	// Draw background image, or rect
+	if !d.appendTitleBackground(pdf, d.endX(width), d.marginTop(), width, 10) {
+		pdf.SetFillColor(d.Options.Theme.DarkBgColor[0], d.Options.Theme.DarkBgColor[1], d.Options.Theme.DarkBgColor[2])
+		pdf.Rect(d.endX(width), d.marginTop(), width, 10, "F")
+	}
 
 	// Draw text
-	pdf.SetFont("Helvetica", "", 14)
-	pdf.CellFormat(80, 10, encodeString(title), "0", 0, "C", false, 0, "")
+	pdf.SetXY(d.endX(width), d.marginTop())
+	pdf.CellFormat(width, 10, title, "0", 0, "C", false, 0, "")
 }
 
-func (d *Document) appendMetas(pdf *gofpdf.Fpdf) {
-	// Append ref
-	refString := fmt.Sprintf("%s: %s", encodeString(d.Options.TextRefTitle), d.Ref)
+// appendTitleBackground draw Options.TitleBackground scaled to cover title box
+// and clipped to it, preserving image aspect ratio. It return false when there
+// is no background or it is not a supported image.
+func (d *Document) appendTitleBackground(pdf *gofpdf.Fpdf, x float64, y float64, width float64, height float64) bool {
+	if len(d.Options.TitleBackground) == 0 {
+		return false
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(d.Options.TitleBackground))
+	if err != nil || config.Width == 0 || config.Height == 0 {
+		return false
+	}
 
-	pdf.SetXY(120, BaseMarginTop+11)
-	pdf.SetFont("Helvetica", "", 8)
-	pdf.CellFormat(80, 4, encodeString(refString), "0", 0, "R", false, 0, "")
+	imageType, ok := logoImageTypes["image/"+format]
+	if !ok {
+		return false
+	}
 
-	// Append version
-	if len(d.Version) > 0 {
-		versionString := fmt.Sprintf("%s: %s", encodeString(d.Options.TextVersionTitle), d.Version)
-		pdf.SetXY(120, BaseMarginTop+15)
-		pdf.SetFont("Helvetica", "", 8)
-		pdf.CellFormat(80, 4, encodeString(versionString), "0", 0, "R", false, 0, "")
+	// Register image in pdf
+	fileName := imageName("title-background", d.Options.TitleBackground)
+	imageOpt := gofpdf.ImageOptions{ImageType: imageType}
+	if pdf.RegisterImageOptionsReader(fileName, imageOpt, bytes.NewReader(d.Options.TitleBackground)) == nil || !pdf.Ok() {
+		return false
+	}
+
+	// Cover box, overflow being centered and clipped
+	scale := math.Max(width/float64(config.Width), height/float64(config.Height))
+	imageWidth := float64(config.Width) * scale
+	imageHeight := float64(config.Height) * scale
+
+	pdf.ClipRect(x, y, width, height, false)
+	pdf.ImageOptions(fileName, x-(imageWidth-width)/2, y-(imageHeight-height)/2, imageWidth, imageHeight, false, imageOpt, 0, "")
+	pdf.ClipEnd()
+
+	return true
+}
+
+// appendMetas draw ref, version, date and due date, returning metas bottom
+func (d *Document) appendMetas(pdf *gofpdf.Fpdf) float64 {
+	// Append ref
+	refString := fmt.Sprintf("%s: %s", d.Options.encodeString(d.Options.TextRefTitle), d.Ref)
+
+	pdf.SetXY(d.endX(80), d.marginTop()+11)
+	pdf.SetFont(d.Options.fontFamily(), "", 8)
+	pdf.CellFormat(80, 4, d.Options.encodeString(refString), "0", 0, d.Options.align("R"), false, 0, "")
+
+	// Append version, its line is reserved even when empty so date position is
+	// stable, unless version is hidden
+	dateY := d.marginTop() + 19
+	if d.Options.VersionDisplay == VersionDisplayHide {
+		dateY = d.marginTop() + 15
+	} else if len(d.Version) > 0 {
+		versionString := fmt.Sprintf("%s: %s", d.Options.encodeString(d.Options.TextVersionTitle), d.Version)
+		pdf.SetXY(d.endX(80), d.marginTop()+15)
+		pdf.SetFont(d.Options.fontFamily(), "", 8)
+		pdf.CellFormat(80, 4, d.Options.encodeString(versionString), "0", 0, d.Options.align("R"), false, 0, "")
 	}
 
 	// Append date
-	date := time.Now().Format("02/01/2006")
-	if len(d.Date) > 0 {
-		date = d.Date
-	}
-	dateString := fmt.Sprintf("%s: %s", encodeString(d.Options.TextDateTitle), date)
-	pdf.SetXY(120, BaseMarginTop+19)
-	pdf.SetFont("Helvetica", "", 8)
-	pdf.CellFormat(80, 4, encodeString(dateString), "0", 0, "R", false, 0, "")
+	dateString := fmt.Sprintf("%s: %s", d.Options.encodeString(d.Options.TextDateTitle), d.date())
+	pdf.SetXY(d.endX(80), dateY)
+	pdf.SetFont(d.Options.fontFamily(), "", 8)
+	pdf.CellFormat(80, 4, d.Options.encodeString(dateString), "0", 0, d.Options.align("R"), false, 0, "")
+
+	// Append due date
+	dueDate := d.dueDate()
+	if len(dueDate) == 0 {
+		return dateY + 4
+	}
+
+	dueDateString := fmt.Sprintf("%s: %s", d.Options.encodeString(d.Options.TextDueDateTitle), dueDate)
+	pdf.SetXY(d.endX(80), dateY+4)
+	pdf.CellFormat(80, 4, d.Options.encodeString(dueDateString), "0", 0, d.Options.align("R"), false, 0, "")
+
+	return dateY + 8
 }
 
 func (d *Document) appendDescription(pdf *gofpdf.Fpdf) {
 	if len(d.Description) > 0 {
 		pdf.SetY(pdf.GetY() + 10)
-		pdf.SetFont("Helvetica", "", 10)
-		pdf.MultiCell(190, 5, encodeString(d.Description), "B", "L", false)
+		pdf.SetFont(d.Options.fontFamily(), "", 10)
+
+		// Draw line by line to break page before exceeding usable height
+		lines := pdf.SplitLines([]byte(d.Options.encodeString(d.Description)), d.contentWidth())
+		for i, line := range lines {
+			if pdf.GetY()+5 > d.maxPageHeight() {
+				pdf.AddPage()
+				pdf.SetFont(d.Options.fontFamily(), "", 10)
+			}
+
+			border := "0"
+			if i == len(lines)-1 {
+				border = "B"
+			}
+
+			pdf.SetX(d.Options.MarginLeft)
+			pdf.CellFormat(d.contentWidth(), 5, string(line), border, 1, d.Options.align("L"), false, 0, "")
+		}
+
+		// Start items table on next page if its header can't fit
+		if pdf.GetY()+20 > d.maxPageHeight() {
+			pdf.AddPage()
+		}
 	}
 }
 
 func (d *Document) drawsTableTitles(pdf *gofpdf.Fpdf) {
+	// Apply cell padding
+	if d.Options.ItemsCellPadding > 0 {
+		defer pdf.SetCellMargin(pdf.GetCellMargin())
+		pdf.SetCellMargin(d.Options.ItemsCellPadding)
+	}
+
+	// Columns titles
+	totalTitle := d.Options.TextItemsTotalTTCTitle
+	if d.Options.ItemsTotalColumn == ItemsTotalNet {
+		totalTitle = d.Options.TextItemsTotalNetTitle
+	}
+
+	titles := map[string]string{
+		ItemColumnIndex:     d.Options.TextItemsIndexTitle,
+		ItemColumnName:      d.Options.TextItemsNameTitle,
+		ItemColumnUnitPrice: d.Options.TextItemsUnitCostTitle,
+		ItemColumnQuantity:  d.Options.TextItemsQuantityTitle,
+		ItemColumnTotalHT:   d.Options.TextItemsTotalHTTitle,
+		ItemColumnDiscount:  d.Options.TextItemsDiscountTitle,
+		ItemColumnTax:       d.Options.TextItemsTaxTitle,
+		ItemColumnTotalTTC:  totalTitle,
+	}
+
 	// Draw table titles
-	pdf.SetX(10)
+	pdf.SetX(d.Options.MarginLeft)
 	pdf.SetY(pdf.GetY() + 5)
-	pdf.SetFont("Helvetica", "B", 8)
+	pdf.SetFont(d.Options.fontFamily(), "B", 8)
 
 	// Draw rec
-	pdf.SetFillColor(GreyBgColor[0], GreyBgColor[1], GreyBgColor[2])
-	pdf.Rect(10, pdf.GetY(), 190, 6, "F")
-
-	// Name
-	pdf.SetX(ItemColNameOffset)
-	pdf.CellFormat(
-		ItemColUnitPriceOffset-ItemColNameOffset,
-		6,
-		encodeString(d.Options.TextItemsNameTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Unit price
-	pdf.SetX(ItemColUnitPriceOffset)
-	pdf.CellFormat(
-		ItemColQuantityOffset-ItemColUnitPriceOffset,
-		6,
-		encodeString(d.Options.TextItemsUnitCostTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Quantity
-	pdf.SetX(ItemColQuantityOffset)
-	pdf.CellFormat(
-		ItemColTaxOffset-ItemColQuantityOffset,
-		6,
-		encodeString(d.Options.TextItemsQuantityTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Total HT
-	pdf.SetX(ItemColTotalHTOffset)
-	pdf.CellFormat(
-		ItemColTaxOffset-ItemColTotalHTOffset,
-		6,
-		encodeString(d.Options.TextItemsTotalHTTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Tax
-	pdf.SetX(ItemColTaxOffset)
-	pdf.CellFormat(
-		ItemColDiscountOffset-ItemColTaxOffset,
-		6,
-		encodeString(d.Options.TextItemsTaxTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Discount
-	pdf.SetX(ItemColDiscountOffset)
-	pdf.CellFormat(
-		ItemColTotalTTCOffset-ItemColDiscountOffset,
-		6,
-		encodeString(d.Options.TextItemsDiscountTitle),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// TOTAL TTC
-	pdf.SetX(ItemColTotalTTCOffset)
-	pdf.CellFormat(190-ItemColTotalTTCOffset, 6, encodeString(d.Options.TextItemsTotalTTCTitle), "0", 0, "", false, 0, "")
+	pdf.SetFillColor(d.Options.Theme.GreyBgColor[0], d.Options.Theme.GreyBgColor[1], d.Options.Theme.GreyBgColor[2])
+	pdf.Rect(d.Options.MarginLeft, pdf.GetY(), d.contentWidth(), 6, "F")
+
+	columns := d.ColumnLayout()
+	for _, col := range columns {
+		pdf.SetX(col.X)
+		pdf.CellFormat(col.Width, 6, d.Options.encodeString(titles[col.Name]), "0", 0, d.Options.align(col.Align), false, 0, "")
+	}
+
+	d.drawColumnsDividers(columns, pdf.GetY(), pdf.GetY()+6, pdf)
+}
+
+// drawColumnsDividers draw items table vertical lines between top and bottom,
+// at columns offsets, when TableBorders is full
+func (d *Document) drawColumnsDividers(columns []ColumnBounds, top float64, bottom float64, pdf *gofpdf.Fpdf) {
+	if d.Options.TableBorders != TableBordersFull {
+		return
+	}
+
+	pdf.SetDrawColor(d.Options.Theme.DarkBgColor[0], d.Options.Theme.DarkBgColor[1], d.Options.Theme.DarkBgColor[2])
+	for _, col := range columns {
+		pdf.Line(col.X, top, col.X, bottom)
+	}
+
+	right := d.Options.MarginLeft + d.contentWidth()
+	pdf.Line(right, top, right, bottom)
+	pdf.SetDrawColor(0, 0, 0)
 }
 
-func (d *Document) appendItems(pdf *gofpdf.Fpdf) {
+// appendItems draw items table, ctx being checked before each row
+func (d *Document) appendItems(ctx context.Context, pdf *gofpdf.Fpdf) error {
 	d.drawsTableTitles(pdf)
 
-	pdf.SetX(10)
+	pdf.SetX(d.Options.MarginLeft)
 	pdf.SetY(pdf.GetY() + 8)
-	pdf.SetFont("Helvetica", "", 8)
+	pdf.SetFont(d.Options.fontFamily(), "", 8)
 
-	for i := 0; i < len(d.Items); i++ {
-		item := d.Items[i]
+	columns := d.ColumnLayout()
 
-		// Check item tax
-		if item.Tax == nil {
-			item.Tax = d.DefaultTax
-		}
+	// Render grouped totals only when there is too many items
+	items := d.activeItems()
+	if d.summarizeItems() {
+		items = d.summaryItems()
+	}
 
-		// Append to pdf
-		item.appendColTo(d.Options, pdf)
+	if d.Options.GroupItems && !d.summarizeItems() {
+		if err := d.appendGroupedItems(ctx, items, columns, pdf); err != nil {
+			return err
+		}
+	} else {
+		for i, item := range items {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 
-		if pdf.GetY() > MaxPageHeight {
-			// Add page
-			pdf.AddPage()
-			d.drawsTableTitles(pdf)
-			pdf.SetFont("Helvetica", "", 8)
+			d.appendItemRow(item, i, columns, pdf)
 		}
+	}
+
+	// Append summary note
+	if d.summarizeItems() {
+		d.appendItemsSummaryNote(pdf)
+	}
+
+	return nil
+}
+
+// appendItemRow draw item row, index being its row number from 0
+func (d *Document) appendItemRow(item *Item, index int, columns []ColumnBounds, pdf *gofpdf.Fpdf) {
+	cols := columnsByName(columns)
+
+	// Add page when row, with its wrapped name and description, does not fit
+	rowHeight := item.height(d.Options, cols, pdf)
+	if index > 0 && pdf.GetY()+rowHeight > d.maxPageHeight() {
+		d.appendItemsPage(pdf)
+	}
+
+	// Draw alternate row background, continuing across pages
+	baseY := pdf.GetY()
+	if d.Options.AlternateRowColors && index%2 == 1 {
+		pdf.SetFillColor(d.Options.Theme.AltRowBgColor[0], d.Options.Theme.AltRowBgColor[1], d.Options.Theme.AltRowBgColor[2])
+		pdf.Rect(d.Options.MarginLeft, baseY-2, d.contentWidth(), rowHeight+4, "F")
+	}
+
+	// Append to pdf
+	item.appendColTo(d.Options, columns, pdf)
+
+	// Append row number
+	if d.Options.ShowItemIndex {
+		bottomY := pdf.GetY()
+		pdf.SetXY(cols[ItemColumnIndex].X, baseY)
+		pdf.CellFormat(cols[ItemColumnIndex].Width, bottomY-baseY, fmt.Sprintf("%d", index+1), "0", 0, d.Options.align(cols[ItemColumnIndex].Align), false, 0, "")
+		pdf.SetY(bottomY)
+	}
 
-		pdf.SetX(10)
-		pdf.SetY(pdf.GetY() + 6)
+	// Draw borders around row, so they are redrawn with each row after page breaks
+	if d.Options.TableBorders == TableBordersHorizontal || d.Options.TableBorders == TableBordersFull {
+		topY, bottomY := itemRowBorders(baseY, pdf.GetY())
+		d.drawColumnsDividers(columns, topY, bottomY, pdf)
+
+		pdf.SetDrawColor(d.Options.Theme.DarkBgColor[0], d.Options.Theme.DarkBgColor[1], d.Options.Theme.DarkBgColor[2])
+		pdf.Line(d.Options.MarginLeft, bottomY, d.Options.MarginLeft+d.contentWidth(), bottomY)
+		pdf.SetDrawColor(0, 0, 0)
 	}
+
+	pdf.SetX(d.Options.MarginLeft)
+	pdf.SetY(pdf.GetY() + 6)
+}
+
+// itemRowBorders return top and bottom offsets of borders of an item row whose
+// cells start at baseY and end at endY. Rows are 6mm apart and start 8mm below
+// 6mm high table titles, so each row border slot ends where next row one starts,
+// first row one starting at titles bottom.
+func itemRowBorders(baseY float64, endY float64) (float64, float64) {
+	return baseY - 2, endY + 4
+}
+
+// appendItemsPage add a page and draw items table titles on it
+func (d *Document) appendItemsPage(pdf *gofpdf.Fpdf) {
+	pdf.AddPage()
+
+	// Mark table as continued, above repeated titles
+	if len(d.Options.TableContinuedLabel) > 0 {
+		pdf.SetFont(d.Options.fontFamily(), "", SmallTextFontSize)
+		pdf.SetTextColor(d.Options.Theme.GreyTextColor[0], d.Options.Theme.GreyTextColor[1], d.Options.Theme.GreyTextColor[2])
+		pdf.SetX(d.Options.MarginLeft)
+		pdf.CellFormat(d.contentWidth(), 4, d.Options.encodeString(d.Options.TableContinuedLabel), "0", 0, d.Options.align("L"), false, 0, "")
+		pdf.SetTextColor(d.Options.Theme.BaseTextColor[0], d.Options.Theme.BaseTextColor[1], d.Options.Theme.BaseTextColor[2])
+	}
+
+	d.drawsTableTitles(pdf)
+	pdf.SetFont(d.Options.fontFamily(), "", 8)
+	pdf.SetX(d.Options.MarginLeft)
+	pdf.SetY(pdf.GetY() + 8)
+}
+
+// notesAfterTotals return true if notes are rendered after totals, on full
+// content width. Position is taken from NotesMode and NotesFullWidth when
+// NotesPosition is unset.
+func (d *Document) notesAfterTotals() bool {
+	switch d.Options.NotesPosition {
+	case NotesPositionBelowTotals:
+		return true
+	case NotesPositionBesideTotals, NotesPositionAboveTotals:
+		return false
+	}
+
+	return d.Options.NotesMode != NotesModeClamp || d.Options.NotesFullWidth
+}
+
+// notesBesideTotals return true if notes are clamped on the left of totals
+func (d *Document) notesBesideTotals() bool {
+	return d.Options.NotesPosition != NotesPositionAboveTotals && !d.notesAfterTotals()
+}
+
+// notesHTML return notes as basic html, line breaks being converted to <br>
+func (d *Document) notesHTML() string {
+	notes := strings.Replace(d.Notes, "\r\n", "\n", -1)
+	notes = strings.Replace(notes, "\n", "<br>", -1)
+	return d.Options.encodeString(notes)
 }
 
 func (d *Document) appendNotes(pdf *gofpdf.Fpdf) {
-	if len(d.Notes) == 0 {
+	if len(d.Notes) == 0 || !d.notesBesideTotals() {
 		return
 	}
 
 	currentY := pdf.GetY()
 
-	pdf.SetFont("Helvetica", "", 9)
-	pdf.SetX(BaseMargin)
-	pdf.SetRightMargin(100)
+	pdf.SetFont(d.Options.fontFamily(), "", d.Options.NotesFontSize)
+	pdf.SetX(d.Options.MarginLeft)
+	if d.Options.RTL {
+		pdf.SetLeftMargin(100)
+	} else {
+		pdf.SetRightMargin(100)
+	}
 	pdf.SetY(currentY + 10)
 
 	_, lineHt := pdf.GetFontSize()
 	html := pdf.HTMLBasicNew()
-	html.Write(lineHt, encodeString(d.Notes))
+	html.Write(lineHt, d.notesHTML())
 
-	pdf.SetRightMargin(BaseMargin)
+	pdf.SetLeftMargin(d.Options.MarginLeft)
+	pdf.SetRightMargin(d.Options.MarginRight)
 	pdf.SetY(currentY)
 }
 
-func (d *Document) appendTotal(pdf *gofpdf.Fpdf) {
-	ac := accounting.Accounting{
-		Symbol:    encodeString(d.Options.CurrencySymbol),
-		Precision: d.Options.CurrencyPrecision,
-		Thousand:  d.Options.CurrencyThousand,
-		Decimal:   d.Options.CurrencyDecimal,
+// appendNotesAboveTotals draw notes on full content width below items, totals
+// being drawn after them
+func (d *Document) appendNotesAboveTotals(pdf *gofpdf.Fpdf) {
+	if len(d.Notes) == 0 || d.Options.NotesPosition != NotesPositionAboveTotals {
+		return
 	}
 
-	// Get total (without tax)
-	total, _ := decimal.NewFromString("0")
+	pdf.SetFont(d.Options.fontFamily(), "", d.Options.NotesFontSize)
+	pdf.SetXY(d.Options.MarginLeft, pdf.GetY()+10)
 
-	for _, item := range d.Items {
-		total = total.Add(item.totalWithoutTaxAndWithDiscount())
-	}
+	// Html writer flows on next pages with auto page break
+	_, lineHt := pdf.GetFontSize()
+	html := pdf.HTMLBasicNew()
+	html.Write(lineHt, d.notesHTML())
 
-	// Apply document discount
-	totalWithDiscount := decimal.NewFromFloat(0)
-	if d.Discount != nil {
-		discountType, discountNumber := d.Discount.getDiscount()
+	pdf.SetY(pdf.GetY() + lineHt)
+}
 
-		if discountType == "amount" {
-			totalWithDiscount = total.Sub(discountNumber)
-		} else {
-			// Percent
-			toSub := total.Mul(discountNumber.Div(decimal.NewFromFloat(100)))
-			totalWithDiscount = total.Sub(toSub)
-		}
+func (d *Document) appendNotesAfterTotals(pdf *gofpdf.Fpdf) {
+	if len(d.Notes) == 0 || !d.notesAfterTotals() {
+		return
 	}
 
-	// Tax
-	totalTax := decimal.NewFromFloat(0)
-	if d.Discount == nil {
-		for _, item := range d.Items {
-			totalTax = totalTax.Add(item.taxWithDiscount())
-		}
+	if d.Options.NotesMode == NotesModeSeparatePage {
+		pdf.AddPage()
 	} else {
-		discountType, discountAmount := d.Discount.getDiscount()
-		discountPercent := discountAmount
-		if discountType == "amount" {
-			// Get percent from total discounted
-			discountPercent = discountAmount.Mul(decimal.NewFromFloat(100)).Div(totalWithDiscount)
-		}
+		pdf.SetY(pdf.GetY() + 15)
+	}
 
-		for _, item := range d.Items {
-			if item.Tax != nil {
-				taxType, taxAmount := item.Tax.getTax()
-				if taxType == "amount" {
-					// If tax type is amount, juste add amount to tax
-					totalTax = totalTax.Add(taxAmount)
-				} else {
-					// Else, remove doc discount % from item total without tax and item discount
-					itemTotal := item.totalWithoutTaxAndWithDiscount()
-					toSub := discountPercent.Mul(itemTotal).Div(decimal.NewFromFloat(100))
-					itemTotalDiscounted := itemTotal.Sub(toSub)
-
-					// Then recompute tax on itemTotalDiscounted
-					itemTaxDiscounted := taxAmount.Mul(itemTotalDiscounted).Div(decimal.NewFromFloat(100))
-
-					totalTax = totalTax.Add(itemTaxDiscounted)
-				}
-			}
+	pdf.SetFont(d.Options.fontFamily(), "", d.Options.NotesFontSize)
+	pdf.SetX(d.Options.MarginLeft)
+
+	// Html writer flows on next pages with auto page break
+	_, lineHt := pdf.GetFontSize()
+	html := pdf.HTMLBasicNew()
+	html.Write(lineHt, d.notesHTML())
+}
+
+// appendTotal draw totals block and return its bounds
+func (d *Document) appendTotal(pdf *gofpdf.Fpdf) Rect {
+	x := d.endX(80)
+	y := pdf.GetY() + 10
+	d.drawTotals(pdf, x, y)
+
+	// Last row is 10mm high
+	bounds := Rect{X: x, Y: y, Width: 80, Height: pdf.GetY() + 10 - y}
+	d.appendCurrencyNote(pdf)
+	d.appendAmountInWords(pdf)
+
+	return bounds
+}
+
+// drawTotals draw totals block with its top left corner at x, y
+func (d *Document) drawTotals(pdf *gofpdf.Fpdf, x float64, y float64) {
+	ac := d.Options.moneyFormatter()
+
+	// Compute totals
+	totals := d.computeTotals()
+
+	// When prices include tax, totals before tax row are gross amounts
+	total := totals.total
+	totalWithDiscount := totals.totalWithDiscount
+	if d.Options.PricesIncludeTax {
+		total = totals.totalGross
+		totalWithDiscount = totals.totalWithTax.Sub(totals.shipping)
+	}
+
+	// Credit notes display totals as negative amounts, computation is unchanged
+	amount := func(value decimal.Decimal) string {
+		if d.Type == CreditNote {
+			value = value.Abs().Neg()
 		}
+		return ac.FormatMoneyDecimal(value)
 	}
 
-	// finalTotal
-	totalWithTax := total.Add(totalTax)
-	if d.Discount != nil {
-		totalWithTax = totalWithDiscount.Add(totalTax)
+	// Titles and amounts columns, swapped when RTL
+	titleX, amountX := x, x+40
+	titleTextX, amountTextX := x, x+42
+	if d.Options.RTL {
+		titleX, amountX = x+40, x
+		titleTextX, amountTextX = x+42, x
 	}
 
-	pdf.SetY(pdf.GetY() + 10)
-	pdf.SetFont("Helvetica", "", LargeTextFontSize)
-	pdf.SetTextColor(BaseTextColor[0], BaseTextColor[1], BaseTextColor[2])
+	pdf.SetXY(x, y)
+	pdf.SetFont(d.Options.fontFamily(), "", LargeTextFontSize)
+	pdf.SetTextColor(d.Options.Theme.BaseTextColor[0], d.Options.Theme.BaseTextColor[1], d.Options.Theme.BaseTextColor[2])
 
 	// Draw TOTAL HT title
-	pdf.SetX(120)
-	pdf.SetFillColor(DarkBgColor[0], DarkBgColor[1], DarkBgColor[2])
-	pdf.Rect(120, pdf.GetY(), 40, 10, "F")
-	pdf.CellFormat(38, 10, encodeString(d.Options.TextTotalTotal), "0", 0, "R", false, 0, "")
+	pdf.SetX(titleTextX)
+	pdf.SetFillColor(d.Options.Theme.DarkBgColor[0], d.Options.Theme.DarkBgColor[1], d.Options.Theme.DarkBgColor[2])
+	pdf.Rect(titleX, pdf.GetY(), 40, 10, "F")
+	pdf.CellFormat(38, 10, d.Options.encodeString(d.Options.TextTotalTotal), "0", 0, d.Options.align("R"), false, 0, "")
 
 	// Draw TOTAL HT amount
-	pdf.SetX(162)
-	pdf.SetFillColor(GreyBgColor[0], GreyBgColor[1], GreyBgColor[2])
-	pdf.Rect(160, pdf.GetY(), 40, 10, "F")
-	pdf.CellFormat(40, 10, ac.FormatMoneyDecimal(total), "0", 0, "L", false, 0, "")
+	pdf.SetX(amountTextX)
+	pdf.SetFillColor(d.Options.Theme.GreyBgColor[0], d.Options.Theme.GreyBgColor[1], d.Options.Theme.GreyBgColor[2])
+	pdf.Rect(amountX, pdf.GetY(), 40, 10, "F")
+	pdf.CellFormat(40, 10, amount(total), "0", 0, d.Options.align("L"), false, 0, "")
 
 	if d.Discount != nil {
 		baseY := pdf.GetY() + 10
 
 		// Draw DISCOUNTED title
-		pdf.SetXY(120, baseY)
-		pdf.SetFillColor(DarkBgColor[0], DarkBgColor[1], DarkBgColor[2])
-		pdf.Rect(120, pdf.GetY(), 40, 15, "F")
+		pdf.SetXY(titleTextX, baseY)
+		pdf.SetFillColor(d.Options.Theme.DarkBgColor[0], d.Options.Theme.DarkBgColor[1], d.Options.Theme.DarkBgColor[2])
+		pdf.Rect(titleX, pdf.GetY(), 40, 15, "F")
 
 		// title
-		pdf.CellFormat(38, 7.5, encodeString(d.Options.TextTotalDiscounted), "0", 0, "BR", false, 0, "")
+		pdf.CellFormat(38, 7.5, d.Options.encodeString(d.Options.TextTotalDiscounted), "0", 0, d.Options.align("BR"), false, 0, "")
 
 		// description
-		pdf.SetXY(120, baseY+7.5)
-		pdf.SetFont("Helvetica", "", BaseTextFontSize)
-		pdf.SetTextColor(GreyTextColor[0], GreyTextColor[1], GreyTextColor[2])
+		pdf.SetXY(titleTextX, baseY+7.5)
+		pdf.SetFont(d.Options.fontFamily(), "", BaseTextFontSize)
+		pdf.SetTextColor(d.Options.Theme.GreyTextColor[0], d.Options.Theme.GreyTextColor[1], d.Options.Theme.GreyTextColor[2])
 
 		var descString bytes.Buffer
 		discountType, discountAmount := d.Discount.getDiscount()
-		if discountType == "percent" {
+		if len(d.Discount.Label) > 0 {
+			descString.WriteString(d.Options.encodeString(d.Discount.Label))
+			descString.WriteString(" -")
+			if discountType == "percent" {
+				descString.WriteString(discountAmount.String())
+				descString.WriteString(" %")
+			} else {
+				descString.WriteString(ac.FormatMoneyDecimal(discountAmount))
+			}
+		} else if discountType == "percent" {
 			descString.WriteString("-")
 			descString.WriteString(discountAmount.String())
 			descString.WriteString(" % / -")
@@ -410,59 +733,188 @@ func (d *Document) appendTotal(pdf *gofpdf.Fpdf) {
 			descString.WriteString("-")
 			descString.WriteString(ac.FormatMoneyDecimal(discountAmount))
 			descString.WriteString(" / -")
-			descString.WriteString(discountAmount.Mul(decimal.NewFromFloat(100)).Div(total).StringFixed(2))
+			descString.WriteString(d.Discount.percentOf(total).StringFixed(2))
 			descString.WriteString(" %")
 		}
 
-		pdf.CellFormat(38, 7.5, descString.String(), "0", 0, "TR", false, 0, "")
+		pdf.CellFormat(38, 7.5, descString.String(), "0", 0, d.Options.align("TR"), false, 0, "")
 
-		pdf.SetFont("Helvetica", "", LargeTextFontSize)
-		pdf.SetTextColor(BaseTextColor[0], BaseTextColor[1], BaseTextColor[2])
+		pdf.SetFont(d.Options.fontFamily(), "", LargeTextFontSize)
+		pdf.SetTextColor(d.Options.Theme.BaseTextColor[0], d.Options.Theme.BaseTextColor[1], d.Options.Theme.BaseTextColor[2])
 
 		// Draw DISCOUNT amount
 		pdf.SetY(baseY)
-		pdf.SetX(162)
-		pdf.SetFillColor(GreyBgColor[0], GreyBgColor[1], GreyBgColor[2])
-		pdf.Rect(160, pdf.GetY(), 40, 15, "F")
-		pdf.CellFormat(40, 15, ac.FormatMoneyDecimal(totalWithDiscount), "0", 0, "L", false, 0, "")
+		pdf.SetX(amountTextX)
+		pdf.SetFillColor(d.Options.Theme.GreyBgColor[0], d.Options.Theme.GreyBgColor[1], d.Options.Theme.GreyBgColor[2])
+		pdf.Rect(amountX, pdf.GetY(), 40, 15, "F")
+		pdf.CellFormat(40, 15, amount(totalWithDiscount), "0", 0, d.Options.align("L"), false, 0, "")
 		pdf.SetY(pdf.GetY() + 15)
 	} else {
 		pdf.SetY(pdf.GetY() + 10)
 	}
 
-	// Draw TAX title
-	pdf.SetX(120)
-	pdf.SetFillColor(DarkBgColor[0], DarkBgColor[1], DarkBgColor[2])
-	pdf.Rect(120, pdf.GetY(), 40, 10, "F")
-	pdf.CellFormat(38, 10, encodeString(d.Options.TextTotalTax), "0", 0, "R", false, 0, "")
+	// Draw SHIPPING row
+	if d.Shipping != nil {
+		shippingTitle := d.Options.TextTotalShipping
+		if len(d.Shipping.Label) > 0 {
+			shippingTitle = d.Shipping.Label
+		}
+
+		pdf.SetX(titleTextX)
+		pdf.SetFillColor(d.Options.Theme.DarkBgColor[0], d.Options.Theme.DarkBgColor[1], d.Options.Theme.DarkBgColor[2])
+		pdf.Rect(titleX, pdf.GetY(), 40, 10, "F")
+		pdf.CellFormat(38, 10, d.Options.encodeString(shippingTitle), "0", 0, d.Options.align("R"), false, 0, "")
+
+		pdf.SetX(amountTextX)
+		pdf.SetFillColor(d.Options.Theme.GreyBgColor[0], d.Options.Theme.GreyBgColor[1], d.Options.Theme.GreyBgColor[2])
+		pdf.Rect(amountX, pdf.GetY(), 40, 10, "F")
+		pdf.CellFormat(40, 10, amount(totals.shipping), "0", 0, d.Options.align("L"), false, 0, "")
+
+		pdf.SetY(pdf.GetY() + 10)
+	}
 
-	// Draw TAX amount
-	pdf.SetX(162)
-	pdf.SetFillColor(GreyBgColor[0], GreyBgColor[1], GreyBgColor[2])
-	pdf.Rect(160, pdf.GetY(), 40, 10, "F")
-	pdf.CellFormat(40, 10, ac.FormatMoneyDecimal(totalTax), "0", 0, "L", false, 0, "")
+	// Draw TAX row, when prices include tax, tax is shown as a note below total
+	if !d.Options.PricesIncludeTax && !(d.Options.AutoHideEmptyColumns && totals.totalTax.IsZero()) {
+		// Draw TAX title
+		pdf.SetX(titleTextX)
+		pdf.SetFillColor(d.Options.Theme.DarkBgColor[0], d.Options.Theme.DarkBgColor[1], d.Options.Theme.DarkBgColor[2])
+		pdf.Rect(titleX, pdf.GetY(), 40, 10, "F")
+		pdf.CellFormat(38, 10, d.Options.encodeString(d.Options.TextTotalTax), "0", 0, d.Options.align("R"), false, 0, "")
+
+		// Draw TAX amount
+		pdf.SetX(amountTextX)
+		pdf.SetFillColor(d.Options.Theme.GreyBgColor[0], d.Options.Theme.GreyBgColor[1], d.Options.Theme.GreyBgColor[2])
+		pdf.Rect(amountX, pdf.GetY(), 40, 10, "F")
+		pdf.CellFormat(40, 10, amount(totals.totalTax), "0", 0, d.Options.align("L"), false, 0, "")
+
+		pdf.SetY(pdf.GetY() + 10)
+	}
 
 	// Draw TOTAL TTC title
-	pdf.SetY(pdf.GetY() + 10)
-	pdf.SetX(120)
-	pdf.SetFillColor(DarkBgColor[0], DarkBgColor[1], DarkBgColor[2])
-	pdf.Rect(120, pdf.GetY(), 40, 10, "F")
-	pdf.CellFormat(38, 10, encodeString(d.Options.TextTotalWithTax), "0", 0, "R", false, 0, "")
-
-	// Draw TOTAL TTC amount
-	pdf.SetX(162)
-	pdf.SetFillColor(GreyBgColor[0], GreyBgColor[1], GreyBgColor[2])
-	pdf.Rect(160, pdf.GetY(), 40, 10, "F")
-	pdf.CellFormat(40, 10, ac.FormatMoneyDecimal(totalWithTax), "0", 0, "L", false, 0, "")
+	pdf.SetX(titleTextX)
+	pdf.SetFillColor(d.Options.Theme.DarkBgColor[0], d.Options.Theme.DarkBgColor[1], d.Options.Theme.DarkBgColor[2])
+	pdf.Rect(titleX, pdf.GetY(), 40, 10, "F")
+	pdf.CellFormat(38, 10, d.Options.encodeString(d.Options.TextTotalWithTax), "0", 0, d.Options.align("R"), false, 0, "")
+
+	// Draw TOTAL TTC amount (or zero total message)
+	totalWithTaxString := amount(totals.totalWithTax)
+	if totals.totalWithTax.IsZero() && len(d.Options.TextTotalZero) > 0 && d.Type != Proforma {
+		totalWithTaxString = d.Options.encodeString(d.Options.TextTotalZero)
+	}
+
+	pdf.SetX(amountTextX)
+	pdf.SetFillColor(d.Options.Theme.GreyBgColor[0], d.Options.Theme.GreyBgColor[1], d.Options.Theme.GreyBgColor[2])
+	pdf.Rect(amountX, pdf.GetY(), 40, 10, "F")
+	pdf.CellFormat(40, 10, totalWithTaxString, "0", 0, d.Options.align("L"), false, 0, "")
+
+	// Draw WITHHOLDING, PREPAID and AMOUNT DUE rows
+	for _, row := range d.amountDueRows(totals) {
+		pdf.SetY(pdf.GetY() + 10)
+
+		pdf.SetX(titleTextX)
+		pdf.SetFillColor(d.Options.Theme.DarkBgColor[0], d.Options.Theme.DarkBgColor[1], d.Options.Theme.DarkBgColor[2])
+		pdf.Rect(titleX, pdf.GetY(), 40, 10, "F")
+		pdf.CellFormat(38, 10, d.Options.encodeString(row.title), "0", 0, d.Options.align("R"), false, 0, "")
+
+		pdf.SetX(amountTextX)
+		pdf.SetFillColor(d.Options.Theme.GreyBgColor[0], d.Options.Theme.GreyBgColor[1], d.Options.Theme.GreyBgColor[2])
+		pdf.Rect(amountX, pdf.GetY(), 40, 10, "F")
+		pdf.CellFormat(40, 10, amount(row.amount), "0", 0, d.Options.align("L"), false, 0, "")
+	}
+
+	// Draw included tax note
+	if d.Options.PricesIncludeTax {
+		rowY := pdf.GetY()
+
+		pdf.SetXY(x, rowY+11)
+		pdf.SetFont(d.Options.fontFamily(), "", BaseTextFontSize)
+		pdf.SetTextColor(d.Options.Theme.GreyTextColor[0], d.Options.Theme.GreyTextColor[1], d.Options.Theme.GreyTextColor[2])
+		pdf.CellFormat(80, 4, fmt.Sprintf("%s %s", d.Options.encodeString(d.Options.TextTotalTaxIncluded), amount(totals.totalTax)), "0", 0, d.Options.align("R"), false, 0, "")
+
+		pdf.SetFont(d.Options.fontFamily(), "", LargeTextFontSize)
+		pdf.SetTextColor(d.Options.Theme.BaseTextColor[0], d.Options.Theme.BaseTextColor[1], d.Options.Theme.BaseTextColor[2])
+		pdf.SetY(rowY + 5)
+	}
+}
+
+func (d *Document) appendProformaDisclaimer(pdf *gofpdf.Fpdf) {
+	if d.Type != Proforma || len(d.Options.TextProformaDisclaimer) == 0 {
+		return
+	}
+
+	pdf.SetXY(d.endX(80), pdf.GetY()+12)
+	pdf.SetFont(d.Options.fontFamily(), "B", BaseTextFontSize)
+	pdf.MultiCell(80, 4, d.Options.encodeString(d.Options.TextProformaDisclaimer), "0", d.Options.align("R"), false)
+	pdf.SetY(pdf.GetY() - 10)
 }
 
 func (d *Document) appendPaymentTerm(pdf *gofpdf.Fpdf) {
 	if len(d.PaymentTerm) > 0 {
-		paymentTermString := fmt.Sprintf("%s: %s", encodeString(d.Options.TextPaymentTermTitle), encodeString(d.PaymentTerm))
+		paymentTermString := fmt.Sprintf("%s: %s", d.Options.encodeString(d.Options.TextPaymentTermTitle), d.Options.encodeString(d.PaymentTerm))
 		pdf.SetY(pdf.GetY() + 15)
 
-		pdf.SetX(120)
-		pdf.SetFont("Helvetica", "B", 10)
-		pdf.CellFormat(80, 4, paymentTermString, "0", 0, "R", false, 0, "")
+		pdf.SetX(d.endX(80))
+		pdf.SetFont(d.Options.fontFamily(), "B", 10)
+		pdf.CellFormat(80, 4, paymentTermString, "0", 0, d.Options.align("R"), false, 0, "")
+	}
+}
+
+// appendFooterMessage draw FooterMessage centered on full width, just above
+// attachments list and page number area of the last page
+func (d *Document) appendFooterMessage(pdf *gofpdf.Fpdf) {
+	if len(d.FooterMessage) == 0 {
+		return
+	}
+
+	message := d.Options.encodeString(d.FooterMessage)
+	pdf.SetFont(d.Options.fontFamily(), "I", BaseTextFontSize)
+
+	// Message ends where attachments list starts
+	height := 4 * float64(linesCount(pdf.SplitLines([]byte(message), d.contentWidth())))
+	messageY := d.maxPageHeight() + 4 - height
+	if pdf.GetY()+10 > messageY {
+		pdf.AddPage()
+	}
+
+	pdf.SetXY(d.Options.MarginLeft, messageY)
+	pdf.SetTextColor(d.Options.Theme.GreyTextColor[0], d.Options.Theme.GreyTextColor[1], d.Options.Theme.GreyTextColor[2])
+	pdf.MultiCell(d.contentWidth(), 4, message, "0", "C", false)
+
+	// Reset font
+	pdf.SetFont(d.Options.fontFamily(), "", BaseTextFontSize)
+	pdf.SetTextColor(d.Options.Theme.BaseTextColor[0], d.Options.Theme.BaseTextColor[1], d.Options.Theme.BaseTextColor[2])
+}
+
+// appendRemitTo draw the address payments are sent to under payment term,
+// aligned with totals
+func (d *Document) appendRemitTo(pdf *gofpdf.Fpdf) {
+	if d.RemitTo == nil {
+		return
+	}
+
+	var address string
+	if d.RemitTo.Address != nil {
+		address = d.RemitTo.Address.ToString()
+	}
+
+	// Check page height (title, name and address lines)
+	y := pdf.GetY() + 8
+	height := 9 + float64(strings.Count(address, "\n")+1)*4
+	if y+height > d.maxPageHeight() {
+		pdf.AddPage()
+		y = pdf.GetY()
+	}
+
+	x := d.endX(80)
+	pdf.SetXY(x, y)
+	pdf.SetFont(d.Options.fontFamily(), "B", 8)
+	pdf.CellFormat(80, 4, d.Options.encodeString(d.Options.TextRemitToTitle), "0", 2, d.Options.align("R"), false, 0, "")
+
+	pdf.SetFont(d.Options.fontFamily(), "B", 10)
+	pdf.CellFormat(80, 5, d.Options.encodeString(d.RemitTo.Name), "0", 2, d.Options.align("R"), false, 0, "")
+
+	if len(address) > 0 {
+		pdf.SetFont(d.Options.fontFamily(), "", 9)
+		pdf.MultiCell(80, 4, d.Options.encodeString(address), "0", d.Options.align("R"), false)
 	}
 }
@@ -0,0 +1,118 @@
+package generator
+
+// Builder allow to construct a document fluently
+type Builder struct {
+	doc *Document
+	err error
+}
+
+// NewBuilder return a new document builder with provided type and options
+func NewBuilder(docType string, options *Options) *Builder {
+	if options == nil {
+		options = &Options{}
+	}
+
+	doc, err := New(docType, options)
+	if err != nil {
+		doc = &Document{Options: options, Type: docType}
+	}
+
+	return &Builder{doc: doc, err: err}
+}
+
+// WithHeader set header of document
+func (b *Builder) WithHeader(header *HeaderFooter) *Builder {
+	b.doc.SetHeader(header)
+	return b
+}
+
+// WithFooter set footer of document
+func (b *Builder) WithFooter(footer *HeaderFooter) *Builder {
+	b.doc.SetFooter(footer)
+	return b
+}
+
+// WithRef set ref of document
+func (b *Builder) WithRef(ref string) *Builder {
+	b.doc.SetRef(ref)
+	return b
+}
+
+// WithVersion set version of document
+func (b *Builder) WithVersion(version string) *Builder {
+	b.doc.SetVersion(version)
+	return b
+}
+
+// WithDescription set description of document
+func (b *Builder) WithDescription(desc string) *Builder {
+	b.doc.SetDescription(desc)
+	return b
+}
+
+// WithNotes set notes of document
+func (b *Builder) WithNotes(notes string) *Builder {
+	b.doc.SetNotes(notes)
+	return b
+}
+
+// WithCompany set company of document
+func (b *Builder) WithCompany(company *Contact) *Builder {
+	b.doc.SetCompany(company)
+	return b
+}
+
+// WithCustomer set customer of document
+func (b *Builder) WithCustomer(customer *Contact) *Builder {
+	b.doc.SetCustomer(customer)
+	return b
+}
+
+// AddItem append item to document items
+func (b *Builder) AddItem(item *Item) *Builder {
+	b.doc.AppendItem(item)
+	return b
+}
+
+// AddAttachment append attachment to document attachments
+func (b *Builder) AddAttachment(attachment *Attachment) *Builder {
+	b.doc.AppendAttachment(attachment)
+	return b
+}
+
+// WithDate set date of document
+func (b *Builder) WithDate(date string) *Builder {
+	b.doc.SetDate(date)
+	return b
+}
+
+// WithPaymentTerm set payment term of document
+func (b *Builder) WithPaymentTerm(term string) *Builder {
+	b.doc.SetPaymentTerm(term)
+	return b
+}
+
+// WithTax set default tax of document
+func (b *Builder) WithTax(tax *Tax) *Builder {
+	b.doc.SetDefaultTax(tax)
+	return b
+}
+
+// WithDiscount set discount of document
+func (b *Builder) WithDiscount(discount *Discount) *Builder {
+	b.doc.SetDiscount(discount)
+	return b
+}
+
+// Build validate and return the assembled document
+func (b *Builder) Build() (*Document, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	if err := b.doc.Validate(); err != nil {
+		return nil, err
+	}
+
+	return b.doc, nil
+}
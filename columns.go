@@ -0,0 +1,225 @@
+package generator
+
+// Items table columns names
+const (
+	// ItemColumnIndex define the item row number column, see Options.ShowItemIndex
+	ItemColumnIndex string = "index"
+
+	// ItemColumnImage define the item image column, see Item.Image
+	ItemColumnImage string = "image"
+
+	// ItemColumnName define the item name column
+	ItemColumnName string = "name"
+
+	// ItemColumnUnitPrice define the item unit price column
+	ItemColumnUnitPrice string = "unit_price"
+
+	// ItemColumnQuantity define the item quantity column
+	ItemColumnQuantity string = "quantity"
+
+	// ItemColumnTotalHT define the item total without tax column
+	ItemColumnTotalHT string = "total_ht"
+
+	// ItemColumnDiscount define the item discount column
+	ItemColumnDiscount string = "discount"
+
+	// ItemColumnTax define the item tax column
+	ItemColumnTax string = "tax"
+
+	// ItemColumnTotalTTC define the item total column
+	ItemColumnTotalTTC string = "total_ttc"
+)
+
+// ItemColumn define an items table column and its width, relative to other columns widths
+type ItemColumn struct {
+	Name  string  `json:"name" validate:"required,oneof=image name unit_price quantity total_ht discount tax total_ttc"`
+	Width float64 `json:"width" validate:"gt=0"`
+	Align string  `json:"align,omitempty" validate:"omitempty,oneof=L C R"` // One of L, C, R, default to R for numeric columns
+}
+
+// align return column alignment, numeric columns being right aligned by default
+// so decimal points line up
+func (c ItemColumn) align() string {
+	if len(c.Align) > 0 {
+		return c.Align
+	}
+
+	if c.Name == ItemColumnName || c.Name == ItemColumnImage {
+		return "L"
+	}
+
+	return "R"
+}
+
+// ColumnBounds define the horizontal position of an items table column
+type ColumnBounds struct {
+	Name  string  `json:"name"`  // Column name, ex "unit_price"
+	X     float64 `json:"x"`     // Left offset in mm
+	Width float64 `json:"width"` // Width in mm
+	Align string  `json:"align"` // Cells alignment, one of L, C, R, mirrored when RTL
+}
+
+// itemColumns return columns from options, or default columns when unset
+func (d *Document) itemColumns() []ItemColumn {
+	if len(d.Options.ItemColumns) > 0 {
+		return d.Options.ItemColumns
+	}
+
+	return []ItemColumn{
+		{Name: ItemColumnName, Width: ItemColUnitPriceOffset - ItemColNameOffset},
+		{Name: ItemColumnUnitPrice, Width: ItemColQuantityOffset - ItemColUnitPriceOffset},
+		{Name: ItemColumnQuantity, Width: ItemColTotalHTOffset - ItemColQuantityOffset},
+		{Name: ItemColumnTotalHT, Width: ItemColDiscountOffset - ItemColTotalHTOffset},
+		{Name: ItemColumnDiscount, Width: ItemColTaxOffset - ItemColDiscountOffset},
+		{Name: ItemColumnTax, Width: ItemColTotalTTCOffset - ItemColTaxOffset},
+		{Name: ItemColumnTotalTTC, Width: a4Width - BaseMargin - ItemColTotalTTCOffset},
+	}
+}
+
+// visibleItemColumns return item columns, tax and discount columns being left
+// out when no item fills them with Options.AutoHideEmptyColumns, name column
+// getting their width
+func (d *Document) visibleItemColumns() []ItemColumn {
+	columns := d.itemColumns()
+	if !d.Options.AutoHideEmptyColumns {
+		return columns
+	}
+
+	hidden := map[string]bool{
+		ItemColumnTax:      !d.hasItemTaxes(),
+		ItemColumnDiscount: !d.hasItemDiscounts(),
+	}
+
+	freed := 0.0
+	visible := make([]ItemColumn, 0, len(columns))
+	for _, column := range columns {
+		if hidden[column.Name] {
+			freed += column.Width
+			continue
+		}
+
+		visible = append(visible, column)
+	}
+
+	for index := range visible {
+		if visible[index].Name == ItemColumnName {
+			visible[index].Width += freed
+		}
+	}
+
+	return visible
+}
+
+// hasItemTaxes return true if a rendered item has a tax, document default tax included
+func (d *Document) hasItemTaxes() bool {
+	for _, item := range d.activeItems() {
+		if len(item.taxes()) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasItemDiscounts return true if a rendered item has a discount
+func (d *Document) hasItemDiscounts() bool {
+	for _, item := range d.activeItems() {
+		if item.Discount != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasItemImages return true if a rendered item has an image
+func (d *Document) hasItemImages() bool {
+	for _, item := range d.activeItems() {
+		if len(item.Image) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ColumnLayout return the items table columns positions, in reading order.
+// Columns relative widths are scaled to the real content width, then mirrored
+// from right edge when RTL.
+func (d *Document) ColumnLayout() []ColumnBounds {
+	itemColumns := d.visibleItemColumns()
+
+	start := d.Options.MarginLeft
+	columns := make([]ColumnBounds, 0, len(itemColumns)+1)
+	if d.Options.ShowItemIndex {
+		columns = append(columns, ColumnBounds{
+			Name:  ItemColumnIndex,
+			X:     d.mirrorX(start, ItemColIndexWidth),
+			Width: ItemColIndexWidth,
+			Align: ItemColumn{Name: ItemColumnIndex}.align(),
+		})
+		start += ItemColIndexWidth
+	}
+
+	// Default layout get a leading image column when an item has an image
+	if len(d.Options.ItemColumns) == 0 && d.hasItemImages() {
+		width := d.Options.ItemImageSize + 2
+		columns = append(columns, ColumnBounds{
+			Name:  ItemColumnImage,
+			X:     d.mirrorX(start, width),
+			Width: width,
+			Align: ItemColumn{Name: ItemColumnImage}.align(),
+		})
+		start += width
+	}
+
+	totalWidth := 0.0
+	for _, column := range itemColumns {
+		totalWidth += column.Width
+	}
+
+	ratio := (d.Options.MarginLeft + d.contentWidth() - start) / totalWidth
+	for _, column := range itemColumns {
+		width := column.Width * ratio
+		columns = append(columns, ColumnBounds{
+			Name:  column.Name,
+			X:     d.mirrorX(start, width),
+			Width: width,
+			Align: column.align(),
+		})
+		start += width
+	}
+
+	return columns
+}
+
+// validateItemColumns check custom columns include the name column, once each
+func (d *Document) validateItemColumns() error {
+	if d.Options == nil || len(d.Options.ItemColumns) == 0 {
+		return nil
+	}
+
+	names := map[string]bool{}
+	for _, column := range d.Options.ItemColumns {
+		if names[column.Name] {
+			return ErrInvalidItemColumns
+		}
+		names[column.Name] = true
+	}
+
+	if !names[ItemColumnName] {
+		return ErrInvalidItemColumns
+	}
+
+	return nil
+}
+
+// columnsByName index columns bounds by column name
+func columnsByName(columns []ColumnBounds) map[string]ColumnBounds {
+	byName := make(map[string]ColumnBounds, len(columns))
+	for _, column := range columns {
+		byName[column.Name] = column
+	}
+
+	return byName
+}
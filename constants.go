@@ -10,10 +10,101 @@ const (
 	// DeliveryNote define the "delievry note" document type
 	DeliveryNote string = "DELIVERY_NOTE"
 
-	// BaseMargin define base margin used in documents
+	// Proforma define the "proforma invoice" document type
+	Proforma string = "PROFORMA"
+
+	// CreditNote define the "credit note" document type, totals are displayed as negative amounts
+	CreditNote string = "CREDIT_NOTE"
+
+	// NotesModeClamp define notes rendered beside totals
+	NotesModeClamp string = "clamp"
+
+	// NotesModeFlowAfterTotals define notes rendered after totals, flowing on next pages
+	NotesModeFlowAfterTotals string = "flow-after-totals"
+
+	// NotesModeSeparatePage define notes rendered on a separate page after totals
+	NotesModeSeparatePage string = "separate-page"
+
+	// NotesPositionBesideTotals define notes clamped on the left of totals
+	NotesPositionBesideTotals string = "beside-totals"
+
+	// NotesPositionBelowTotals define notes rendered after totals, on full content width,
+	// on a separate page with NotesModeSeparatePage
+	NotesPositionBelowTotals string = "below-totals"
+
+	// NotesPositionAboveTotals define notes rendered between items and totals, on full content width
+	NotesPositionAboveTotals string = "above-totals"
+
+	// ItemsTotalGross define items last column showing line total with tax
+	ItemsTotalGross string = "gross"
+
+	// ItemsTotalNet define items last column showing line total without tax
+	ItemsTotalNet string = "net"
+
+	// ZeroQuantityShow define zero quantity items rendered as other items
+	ZeroQuantityShow string = "show"
+
+	// ZeroQuantitySkip define zero quantity items not rendered nor summed
+	ZeroQuantitySkip string = "skip"
+
+	// ZeroQuantityIncluded define zero quantity items rendered with an "included" label
+	ZeroQuantityIncluded string = "included"
+
+	// VersionDisplayReserve define version meta line always reserved, even when version is empty
+	VersionDisplayReserve string = "reserve"
+
+	// VersionDisplayHide define version meta never rendered, date taking its line
+	VersionDisplayHide string = "hide"
+
+	// RoundingModeNone define amounts never rounded before display
+	RoundingModeNone string = "none"
+
+	// RoundingModeHalfUp define amounts rounded half away from zero
+	RoundingModeHalfUp string = "half-up"
+
+	// RoundingModeBankers define amounts rounded half to even
+	RoundingModeBankers string = "bankers"
+
+	// WithholdingBaseNet define withholding tax computed on total without tax
+	WithholdingBaseNet string = "net"
+
+	// WithholdingBaseGross define withholding tax computed on total with tax
+	WithholdingBaseGross string = "gross"
+
+	// TableBordersNone define items table drawn without borders
+	TableBordersNone string = "none"
+
+	// TableBordersHorizontal define items table drawn with lines between rows
+	TableBordersHorizontal string = "horizontal"
+
+	// TableBordersFull define items table drawn with lines between rows and columns
+	TableBordersFull string = "full"
+
+	// NumberGroupingWestern define amounts digits grouped by thousands, ex 1,234,567
+	NumberGroupingWestern string = "western"
+
+	// NumberGroupingIndian define amounts digits grouped by lakh and crore, ex 12,34,567
+	NumberGroupingIndian string = "indian"
+
+	// LateFeePeriodDay define late fee percent charged per day
+	LateFeePeriodDay string = "day"
+
+	// LateFeePeriodMonth define late fee percent charged per month of 30 days
+	LateFeePeriodMonth string = "month"
+
+	// LateFeePeriodYear define late fee percent charged per year of 365 days
+	LateFeePeriodYear string = "year"
+
+	// FacturXProfileBasic define the Factur-X / ZUGFeRD BASIC profile
+	FacturXProfileBasic string = "BASIC"
+
+	// FacturXProfileEN16931 define the Factur-X / ZUGFeRD EN16931 (comfort) profile
+	FacturXProfileEN16931 string = "EN16931"
+
+	// BaseMargin define base margin used in documents, default of Options.MarginLeft and Options.MarginRight
 	BaseMargin float64 = 10
 
-	// BaseMarginTop define base margin top used in documents
+	// BaseMarginTop define base margin top used in documents, default of Options.MarginTop
 	BaseMarginTop float64 = 20
 
 	// HeaderMarginTop define base header margin top used in documents
@@ -45,6 +136,10 @@ const (
 
 	// ItemColTotalTTCOffset ...
 	ItemColTotalTTCOffset float64 = 175
+
+	// ItemColIndexWidth define the width of the row number column, other
+	// columns being narrowed to make room
+	ItemColIndexWidth float64 = 8
 )
 
 var (
@@ -60,15 +155,18 @@ var (
 	// LargeTextFontSize define the large font size for text in document
 	LargeTextFontSize float64 = 10
 
-	// BaseTextColor define the base color used for text in document
+	// BaseTextColor define the default base color used for text in document, see Theme
 	BaseTextColor = []int{35, 35, 35}
 
-	// GreyTextColor define the base color used for text in document
+	// GreyTextColor define the default base color used for text in document, see Theme
 	GreyTextColor = []int{82, 82, 82}
 
-	// GreyBgColor define the grey background color used for text in document
+	// GreyBgColor define the default grey background color used for text in document, see Theme
 	GreyBgColor = []int{232, 232, 232}
 
-	// DarkBgColor define the grey background color used for text in document
+	// DarkBgColor define the default grey background color used for text in document, see Theme
 	DarkBgColor = []int{212, 212, 212}
+
+	// AltRowBgColor define the default background color of alternate items rows, see Theme
+	AltRowBgColor = []int{246, 246, 246}
 )
@@ -1,9 +1,7 @@
 package generator
 
 import (
-	"bytes"
-	b64 "encoding/base64"
-	"image"
+	"fmt"
 
 	"github.com/jung-kurt/gofpdf"
 )
@@ -11,39 +9,20 @@ import (
 // Contact contact a company informations
 type Contact struct {
 	Name    string   `json:"name,omitempty" validate:"required,min=1,max=256"`
-	Logo    *[]byte  `json:"logo,omitempty"` // Logo byte array
+	Logo    *Logo    `json:"logo,omitempty"`
 	Address *Address `json:"address,omitempty"`
+	TaxID   string   `json:"tax_id,omitempty"` // VAT identification number or tax number, printed under address
 }
 
-func (c *Contact) appendContactTODoc(x float64, y float64, fill bool, logoAlign string, pdf *gofpdf.Fpdf) float64 {
+func (c *Contact) appendContactTODoc(options *Options, x float64, y float64, fill bool, logoAlign string, pdf *gofpdf.Fpdf) float64 {
 	pdf.SetXY(x, y)
 
 	// Logo
-	if c.Logo != nil {
-		// Create filename
-		fileName := b64.StdEncoding.EncodeToString([]byte(c.Name))
-		// Create reader from logo bytes
-		ioReader := bytes.NewReader(*c.Logo)
-		// Get image format
-		_, format, _ := image.DecodeConfig(bytes.NewReader(*c.Logo))
-		// Register image in pdf
-		imageInfo := pdf.RegisterImageOptionsReader(fileName, gofpdf.ImageOptions{
-			ImageType: format,
-		}, ioReader)
-
-		if imageInfo != nil {
-			var imageOpt gofpdf.ImageOptions
-			imageOpt.ImageType = format
-
-			pdf.ImageOptions(fileName, pdf.GetX(), y, 0, 30, false, imageOpt, 0, "")
-
-			pdf.SetY(y + 30)
-		}
-	}
+	c.appendLogo(x, y, pdf)
 
 	// Name
 	if fill {
-		pdf.SetFillColor(GreyBgColor[0], GreyBgColor[1], GreyBgColor[2])
+		pdf.SetFillColor(options.Theme.GreyBgColor[0], options.Theme.GreyBgColor[1], options.Theme.GreyBgColor[2])
 	} else {
 		pdf.SetFillColor(255, 255, 255)
 	}
@@ -55,9 +34,9 @@ func (c *Contact) appendContactTODoc(x float64, y float64, fill bool, logoAlign
 	pdf.Rect(x, pdf.GetY(), 70, 8, "F")
 
 	// Set name
-	pdf.SetFont("Helvetica", "B", 10)
-	pdf.Cell(40, 8, c.Name)
-	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetFont(options.fontFamily(), "B", 10)
+	pdf.CellFormat(70, 8, c.Name, "0", 0, options.align("L"), false, 0, "")
+	pdf.SetFont(options.fontFamily(), "", 10)
 
 	if c.Address != nil {
 		// Address rect
@@ -74,19 +53,102 @@ func (c *Contact) appendContactTODoc(x float64, y float64, fill bool, logoAlign
 		pdf.Rect(x, pdf.GetY()+9, 70, addrRectHeight, "F")
 
 		// Set address
-		pdf.SetFont("Helvetica", "", 10)
+		pdf.SetFont(options.fontFamily(), "", 10)
 		pdf.SetXY(x, pdf.GetY()+10)
-		pdf.MultiCell(70, 5, c.Address.ToString(), "0", "L", false)
+		pdf.MultiCell(70, 5, c.Address.ToString(), "0", options.align("L"), false)
+	}
+
+	if len(c.TaxID) > 0 {
+		// Tax id rect, below address or name
+		top := pdf.GetY() + 2
+		if c.Address == nil {
+			top = pdf.GetY() + 9
+		}
+
+		pdf.Rect(x, top, 70, 6, "F")
+
+		// Set tax id
+		taxIDString := fmt.Sprintf("%s: %s", options.TextTaxIDTitle, c.TaxID)
+		pdf.SetFont(options.fontFamily(), "", 8)
+		pdf.SetXY(x, top)
+		pdf.CellFormat(70, 6, options.encodeString(taxIDString), "0", 0, options.align("L"), false, 0, "")
+		pdf.SetY(top + 5)
 	}
 
 	return pdf.GetY()
 }
 
-func (c *Contact) appendCompanyContactToDoc(pdf *gofpdf.Fpdf) float64 {
-	x, y, _, _ := pdf.GetMargins()
-	return c.appendContactTODoc(x, y, true, "L", pdf)
+// appendLogo draw contact logo at x, y and set y below it
+func (c *Contact) appendLogo(x float64, y float64, pdf *gofpdf.Fpdf) {
+	if c.Logo == nil {
+		return
+	}
+
+	c.Logo.appendTo(c.Name, x, y, pdf)
+}
+
+// Company is drawn on the left, customer on the right, sides being swapped when
+// RTL or Options.SwapPartyColumns, but not both
+func (c *Contact) appendCompanyContactToDoc(options *Options, y float64, pdf *gofpdf.Fpdf) float64 {
+	x := companyX(options, pdf)
+
+	y = appendContactHeading(options, options.TextFromTitle, x, y, pdf)
+	return c.appendContactTODoc(options, x, y, true, "L", pdf)
+}
+
+func (c *Contact) appendCustomerContactToDoc(options *Options, y float64, pdf *gofpdf.Fpdf) float64 {
+	x := customerX(options, pdf)
+
+	y = appendContactHeading(options, options.TextToTitle, x, y, pdf)
+	return c.appendContactTODoc(options, x, y, true, "R", pdf)
+}
+
+// appendShipToContactToDoc draw ship to contact under a heading, on customer side
+func (c *Contact) appendShipToContactToDoc(options *Options, y float64, pdf *gofpdf.Fpdf) float64 {
+	x := customerX(options, pdf)
+
+	y = appendContactHeading(options, options.TextShipToTitle, x, y, pdf)
+	return c.appendContactTODoc(options, x, y, true, "R", pdf)
+}
+
+// appendContactHeading draw a small bold heading at x, y and return contact
+// block y below it, no heading being drawn when title is empty
+func appendContactHeading(options *Options, title string, x float64, y float64, pdf *gofpdf.Fpdf) float64 {
+	if len(title) == 0 {
+		return y
+	}
+
+	pdf.SetXY(x, y)
+	pdf.SetFont(options.fontFamily(), "B", 8)
+	pdf.CellFormat(70, 4, options.encodeString(title), "0", 0, options.align("L"), false, 0, "")
+
+	return y + 5
+}
+
+// companyX return the x offset of company contact block
+func companyX(options *Options, pdf *gofpdf.Fpdf) float64 {
+	left, _, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	if options.partiesSwapped() {
+		return pageWidth - right - 70
+	}
+
+	return left
+}
+
+// customerX return the x offset of customer side contacts blocks
+func customerX(options *Options, pdf *gofpdf.Fpdf) float64 {
+	left, _, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	if options.partiesSwapped() {
+		return left
+	}
+
+	return pageWidth - right - 70
 }
 
-func (c *Contact) appendCustomerContactToDoc(pdf *gofpdf.Fpdf) float64 {
-	return c.appendContactTODoc(130, BaseMarginTop+25, true, "R", pdf)
+// partiesSwapped return true if company is drawn on the right and customer on
+// the left, RTL mirroring being undone by SwapPartyColumns
+func (o *Options) partiesSwapped() bool {
+	return o.RTL != o.SwapPartyColumns
 }
@@ -0,0 +1,129 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// foreignCurrency return true if item amounts are in another currency than
+// document, item currency being compared to document symbol without spaces
+// and to document currency code
+func (i *Item) foreignCurrency(options *Options) bool {
+	currency := strings.TrimSpace(i.Currency)
+	if len(currency) == 0 || currency == strings.TrimSpace(options.CurrencySymbol) {
+		return false
+	}
+
+	return len(options.CurrencyCode) == 0 || !strings.EqualFold(currency, options.CurrencyCode)
+}
+
+// foreignCurrencies return currencies of items priced in another currency than document
+func (d *Document) foreignCurrencies() []string {
+	var currencies []string
+	seen := map[string]bool{}
+
+	for _, item := range d.activeItems() {
+		if item.foreignCurrency(d.Options) && !seen[item.Currency] {
+			seen[item.Currency] = true
+			currencies = append(currencies, item.Currency)
+		}
+	}
+
+	return currencies
+}
+
+// convertible return true when all items foreign currencies have an exchange rate
+func (d *Document) convertible() bool {
+	for _, currency := range d.foreignCurrencies() {
+		if _, ok := d.Options.ExchangeRates[currency]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// convertedItems return items with amounts converted to document currency when
+// all foreign currencies have an exchange rate, items being returned as is otherwise
+func (d *Document) convertedItems(items []*Item) []*Item {
+	if len(d.Options.ExchangeRates) == 0 || !d.convertible() {
+		return items
+	}
+
+	result := make([]*Item, 0, len(items))
+	for _, item := range items {
+		if !item.foreignCurrency(d.Options) {
+			result = append(result, item)
+			continue
+		}
+
+		rate := d.Options.ExchangeRates[item.Currency]
+		converted := *item
+		converted.Currency = ""
+		converted.UnitCost = item.unitCost().Mul(rate).String()
+
+		if taxes := item.taxes(); len(taxes) > 0 {
+			converted.Tax = nil
+			converted.Taxes = make([]*Tax, 0, len(taxes))
+			for _, tax := range taxes {
+				if taxType, taxAmount := tax.getTax(); taxType == "amount" {
+					tax = &Tax{Amount: taxAmount.Mul(rate).String(), Compound: tax.Compound}
+				}
+				converted.Taxes = append(converted.Taxes, tax)
+			}
+		}
+
+		if item.Discount != nil {
+			if discountType, discountAmount := item.Discount.getDiscount(); discountType == "amount" {
+				converted.Discount = &Discount{Amount: discountAmount.Mul(rate).String(), Label: item.Discount.Label}
+			}
+		}
+
+		result = append(result, &converted)
+	}
+
+	return result
+}
+
+// appendCurrencyNote draw a note below totals when items use other currencies
+// than document, with exchange rates used or single currency assumption
+func (d *Document) appendCurrencyNote(pdf *gofpdf.Fpdf) {
+	currencies := d.foreignCurrencies()
+	if len(currencies) == 0 {
+		return
+	}
+
+	var note bytes.Buffer
+	if len(d.Options.ExchangeRates) > 0 && d.convertible() {
+		note.WriteString(d.Options.TextTotalsExchangeRates)
+		for index, currency := range currencies {
+			if index > 0 {
+				note.WriteString(",")
+			}
+			note.WriteString(fmt.Sprintf(
+				" 1 %s = %s %s",
+				strings.TrimSpace(currency),
+				d.Options.ExchangeRates[currency].String(),
+				strings.TrimSpace(d.Options.CurrencySymbol),
+			))
+		}
+	} else {
+		note.WriteString(d.Options.TextTotalsSingleCurrency)
+	}
+
+	y := pdf.GetY() + 11
+	if d.Options.PricesIncludeTax {
+		y += 5
+	}
+
+	pdf.SetXY(d.endX(80), y)
+	pdf.SetFont(d.Options.fontFamily(), "", BaseTextFontSize)
+	pdf.SetTextColor(d.Options.Theme.GreyTextColor[0], d.Options.Theme.GreyTextColor[1], d.Options.Theme.GreyTextColor[2])
+	pdf.MultiCell(80, 4, d.Options.encodeString(note.String()), "0", d.Options.align("R"), false)
+
+	pdf.SetTextColor(d.Options.Theme.BaseTextColor[0], d.Options.Theme.BaseTextColor[1], d.Options.Theme.BaseTextColor[2])
+	pdf.SetY(pdf.GetY() - 10)
+}
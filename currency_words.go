@@ -0,0 +1,35 @@
+package generator
+
+import (
+	"strings"
+)
+
+// CurrencyUnitNames define the names of a currency major and minor units,
+// used when spelling out amounts
+type CurrencyUnitNames struct {
+	Major       string // ex euro
+	MajorPlural string // ex euros
+	Minor       string // ex cent
+	MinorPlural string // ex cents
+}
+
+// currencyUnitNames by ISO 4217 code
+var currencyUnitNames = map[string]CurrencyUnitNames{
+	"AUD": {"dollar", "dollars", "cent", "cents"},
+	"CAD": {"dollar", "dollars", "cent", "cents"},
+	"CHF": {"franc", "francs", "centime", "centimes"},
+	"CNY": {"yuan", "yuan", "fen", "fen"},
+	"EUR": {"euro", "euros", "cent", "cents"},
+	"GBP": {"pound", "pounds", "penny", "pence"},
+	"INR": {"rupee", "rupees", "paisa", "paise"},
+	"JPY": {"yen", "yen", "sen", "sen"},
+	"RUB": {"ruble", "rubles", "kopek", "kopeks"},
+	"SEK": {"krona", "kronor", "öre", "öre"},
+	"USD": {"dollar", "dollars", "cent", "cents"},
+}
+
+// CurrencyUnits return the units names of the currency with provided ISO 4217 code
+func CurrencyUnits(code string) (CurrencyUnitNames, bool) {
+	names, ok := currencyUnitNames[strings.ToUpper(code)]
+	return names, ok
+}
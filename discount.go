@@ -8,22 +8,85 @@ import (
 type Discount struct {
 	Percent string `json:"percent,omitempty"` // Discount in percent ex 17
 	Amount  string `json:"amount,omitempty"`  // Discount in amount ex 123.40
+	Label   string `json:"label,omitempty"`   // Discount reason ex Loyalty discount
 }
 
+// parse return discount type and value as set, amount taking precedence over
+// percent, and an error when value is not a number
+func (t *Discount) parse() (string, decimal.Decimal, error) {
+	return parseRate(t.Percent, t.Amount)
+}
+
+// getDiscount return discount type, percent when unset, and its value. Percents
+// are clamped between 0 and 100, documents rejecting them unless Options.ClampPercents.
 func (t *Discount) getDiscount() (string, decimal.Decimal) {
-	tax := "0"
-	taxType := "percent"
+	discountType, discountAmount, _ := t.parse()
+	if discountType == "percent" {
+		discountAmount = clampPercent(discountAmount)
+	}
+
+	return discountType, discountAmount
+}
+
+// percentOutOfRange return true if discount is a percent lower than 0 or greater than 100
+func (t *Discount) percentOutOfRange() bool {
+	discountType, discountAmount, _ := t.parse()
+	return discountType == "percent" && !discountAmount.Equal(clampPercent(discountAmount))
+}
+
+// Validate check discount value is a number, and a percent between 0 and 100
+func (t *Discount) Validate() error {
+	return t.validate(true)
+}
+
+// validate check discount value is a number, out of range percents being
+// accepted when not strict
+func (t *Discount) validate(strict bool) error {
+	if _, _, err := t.parse(); err != nil {
+		return ErrInvalidNumber
+	}
 
-	if len(t.Percent) > 0 {
-		tax = t.Percent
+	if strict && t.percentOutOfRange() {
+		return ErrPercentOutOfRange
 	}
 
-	if len(t.Amount) > 0 {
-		tax = t.Amount
-		taxType = "amount"
+	return nil
+}
+
+// amountOf return discount amount on total, clamped between zero and total
+// when total is positive
+func (t *Discount) amountOf(total decimal.Decimal) decimal.Decimal {
+	discountType, discountNumber := t.getDiscount()
+
+	amount := discountNumber
+	if discountType == "percent" {
+		amount = total.Mul(discountNumber).Div(decimal.NewFromFloat(100))
+	}
+
+	if !total.IsNegative() {
+		if amount.IsNegative() {
+			return decimal.NewFromFloat(0)
+		}
+
+		if amount.GreaterThan(total) {
+			return total
+		}
 	}
 
-	decVal, _ := decimal.NewFromString(tax)
+	return amount
+}
+
+// percentOf return discount as percent of total, zero when total is zero
+func (t *Discount) percentOf(total decimal.Decimal) decimal.Decimal {
+	if total.IsZero() {
+		return decimal.NewFromFloat(0)
+	}
+
+	return t.amountOf(total).Mul(decimal.NewFromFloat(100)).Div(total)
+}
 
-	return taxType, decVal
+// exceeds return true if discount is a fixed amount greater than positive total
+func (t *Discount) exceeds(total decimal.Decimal) bool {
+	discountType, discountNumber := t.getDiscount()
+	return discountType == "amount" && !total.IsNegative() && discountNumber.GreaterThan(total)
 }
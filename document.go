@@ -1,22 +1,39 @@
 package generator
 
+import (
+	"time"
+)
+
 // Document define base document
 type Document struct {
-	Options      *Options      `json:"options,omitempty"`
-	Header       *HeaderFooter `json:"header,omitempty"`
-	Footer       *HeaderFooter `json:"footer,omitempty"`
-	Type         string        `json:"type,omitempty" validate:"required,oneof=INVOICE DELIVERY_NOTE QUOTATION"`
-	Ref          string        `json:"ref,omitempty" validate:"required,min=1,max=32"`
-	Version      string        `json:"version,omitempty" validate:"max=32"`
-	ClientRef    string        `json:"client_ref,omitempty" validate:"max=64"`
-	Description  string        `json:"description,omitempty" validate:"max=1024"`
-	Notes        string        `json:"notes,omitempty"`
-	Company      *Contact      `json:"company,omitempty" validate:"required"`
-	Customer     *Contact      `json:"customer,omitempty" validate:"required"`
-	Items        []*Item       `json:"items,omitempty"`
-	Date         string        `json:"date,omitempty"`
-	ValidityDate string        `json:"validity_date,omitempty"`
-	PaymentTerm  string        `json:"payment_term,omitempty"`
-	DefaultTax   *Tax          `json:"default_tax,omitempty"`
-	Discount     *Discount     `json:"discount,omitempty"`
+	Options       *Options      `json:"options,omitempty"`
+	Header        *HeaderFooter `json:"header,omitempty"`
+	Footer        *HeaderFooter `json:"footer,omitempty"`
+	Type          string        `json:"type,omitempty" validate:"required,oneof=INVOICE DELIVERY_NOTE QUOTATION PROFORMA CREDIT_NOTE"`
+	Ref           string        `json:"ref,omitempty" validate:"required,min=1,max=32"`
+	Version       string        `json:"version,omitempty" validate:"max=32"`
+	ClientRef     string        `json:"client_ref,omitempty" validate:"max=64"`
+	Description   string        `json:"description,omitempty" validate:"max=1024"`
+	Notes         string        `json:"notes,omitempty"`
+	FooterMessage string        `json:"footer_message,omitempty"` // Thank you or terms message, centered at the bottom of last page
+	Company       *Contact      `json:"company,omitempty" validate:"required"`
+	Customer      *Contact      `json:"customer,omitempty" validate:"required"`
+	ShipTo        *Contact      `json:"ship_to,omitempty"`  // Delivery address, when distinct from customer address
+	RemitTo       *Contact      `json:"remit_to,omitempty"` // Address payments are sent to, when distinct from company address
+	Items         []*Item       `json:"items,omitempty"`
+	Date          string        `json:"date,omitempty"` // Deprecated: use DateTime, parseable dates are reformatted with Options.DateFormat
+	DateTime      time.Time     `json:"date_time,omitempty"`
+	ValidityDate  string        `json:"validity_date,omitempty"`
+	PaymentTerm   string        `json:"payment_term,omitempty"`
+	DueDate       string        `json:"due_date,omitempty"` // Deprecated: use DueDateTime
+	DueDateTime   time.Time     `json:"due_date_time,omitempty"`
+	DefaultTax    *Tax          `json:"default_tax,omitempty"`
+	Discount      *Discount     `json:"discount,omitempty"`
+	Withholding   *Tax          `json:"withholding,omitempty"`              // Withholding tax, subtracted from total with tax
+	Payments      []*Payment    `json:"payments,omitempty" validate:"dive"` // Payments already received, subtracted from amount due
+	LateFee       *LateFee      `json:"late_fee,omitempty"`                 // Interest charged on amount due when overdue
+	Attachments   []*Attachment `json:"attachments,omitempty"`
+	BankDetails   *BankDetails  `json:"bank_details,omitempty"`
+	Shipping      *Shipping     `json:"shipping,omitempty"`
+	Signature     *Signature    `json:"signature,omitempty"`
 }
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// cp1252Specials define the runes of the CP1252 0x80 - 0x9F range, other
+// runes below 0x100 having the same code as in ISO 8859-1
+var cp1252Specials = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// cp1252Fallbacks define ASCII approximations of common runes missing from CP1252
+var cp1252Fallbacks = map[rune]string{
+	// Dashes, quotes and spaces
+	'‐': "-", '‑': "-", '‒': "-", '―': "-", '−': "-",
+	'‛': "'", '′': "'", '‟': "\"", '″': "\"",
+	'\u2002': " ", '\u2003': " ", '\u2007': " ", '\u2009': " ", '\u200A': " ", '\u202F': " ",
+	'\u200B': "", '\uFEFF': "",
+
+	// Symbols
+	'←': "<-", '→': "->", '≤': "<=", '≥': ">=", '≠': "!=", '≈': "~", '№': "No",
+	'₹': "Rs", '₽': "RUB", '₩': "W", '₺': "TL", '₪': "ILS",
+
+	// Latin letters with diacritics
+	'Ā': "A", 'ā': "a", 'Ă': "A", 'ă': "a", 'Ą': "A", 'ą': "a",
+	'Ć': "C", 'ć': "c", 'Č': "C", 'č': "c", 'Ď': "D", 'ď': "d", 'Đ': "D", 'đ': "d",
+	'Ē': "E", 'ē': "e", 'Ę': "E", 'ę': "e", 'Ě': "E", 'ě': "e",
+	'Ğ': "G", 'ğ': "g", 'Ī': "I", 'ī': "i", 'İ': "I", 'ı': "i",
+	'Ł': "L", 'ł': "l", 'Ń': "N", 'ń': "n", 'Ň': "N", 'ň': "n",
+	'Ō': "O", 'ō': "o", 'Ő': "O", 'ő': "o", 'Ř': "R", 'ř': "r",
+	'Ś': "S", 'ś': "s", 'Ş': "S", 'ş': "s", 'Ș': "S", 'ș': "s",
+	'Ť': "T", 'ť': "t", 'Ţ': "T", 'ţ': "t", 'Ț': "T", 'ț': "t",
+	'Ū': "U", 'ū': "u", 'Ů': "U", 'ů': "u", 'Ű': "U", 'ű': "u",
+	'Ź': "Z", 'ź': "z", 'Ż': "Z", 'ż': "z",
+}
+
+// encodable return true if r is in CP1252 or has an ASCII approximation
+func encodable(r rune) bool {
+	if r < 0x80 || (r >= 0xA0 && r <= 0xFF) {
+		return true
+	}
+
+	if _, ok := cp1252Specials[r]; ok {
+		return true
+	}
+
+	_, ok := cp1252Fallbacks[r]
+	return ok
+}
+
+// encodeString translate UTF-8 str to CP1252, used by core fonts.
+// Runes missing from CP1252 are approximated in ASCII, or replaced by "?".
+// Bytes which are not valid UTF-8, as in an already translated string, are
+// kept as is so translation can be applied twice.
+func encodeString(str string) string {
+	var b strings.Builder
+	b.Grow(len(str))
+
+	for len(str) > 0 {
+		r, size := utf8.DecodeRuneInString(str)
+
+		switch {
+		case r == utf8.RuneError && size == 1:
+			b.WriteByte(str[0])
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			b.WriteByte(byte(r))
+		default:
+			if ch, ok := cp1252Specials[r]; ok {
+				b.WriteByte(ch)
+			} else if fallback, ok := cp1252Fallbacks[r]; ok {
+				b.WriteString(fallback)
+			} else {
+				b.WriteByte('?')
+			}
+		}
+
+		str = str[size:]
+	}
+
+	return b.String()
+}
@@ -0,0 +1,120 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDiscountExceedsTotal is returned when an item discount is greater than item total,
+// or a document discount greater than items total with Options.StrictDiscounts
+var ErrDiscountExceedsTotal = errors.New("discount exceeds item total")
+
+// ErrNoItems is returned when a document has no items
+var ErrNoItems = errors.New("document has no items")
+
+// ErrNoDocuments is returned when building a batch without documents
+var ErrNoDocuments = errors.New("batch has no documents")
+
+// ErrNegativeUnitCost is returned when an item unit cost is negative on a document other than a credit note
+var ErrNegativeUnitCost = errors.New("negative unit cost is only allowed on credit notes")
+
+// ErrPercentOutOfRange is returned when a tax or discount percent is not between 0 and 100
+var ErrPercentOutOfRange = errors.New("percent must be between 0 and 100")
+
+// ErrInvalidNumber is returned when a tax or discount percent or amount is not a number
+var ErrInvalidNumber = errors.New("invalid number")
+
+// ErrNegativeQuantity is returned when an item quantity is negative on a document other than a credit note
+var ErrNegativeQuantity = errors.New("negative quantity is only allowed on credit notes")
+
+// ErrWithholdingExceedsTotal is returned when withholding tax percent is greater than 100
+var ErrWithholdingExceedsTotal = errors.New("withholding percent exceeds 100")
+
+// ErrInvalidItemColumns is returned when custom items columns are duplicated or miss the name column
+var ErrInvalidItemColumns = errors.New("item columns must include name column once, without duplicates")
+
+// ErrInvalidIBANLength is returned when an IBAN is not between 15 and 34 characters
+var ErrInvalidIBANLength = errors.New("invalid iban length")
+
+// ErrInvalidIBANFormat is returned when an IBAN does not start with a country code and check digits
+// or contains non alphanumeric characters
+var ErrInvalidIBANFormat = errors.New("invalid iban format")
+
+// ErrInvalidIBANChecksum is returned when an IBAN mod-97 checksum is invalid
+var ErrInvalidIBANChecksum = errors.New("invalid iban checksum")
+
+// ErrInvalidTaxID is returned when an EU VAT identification number is malformed, with Options.StrictTaxIDs
+var ErrInvalidTaxID = errors.New("invalid vat identification number format")
+
+// ErrInvalidOption is returned when an option is not one of its allowed values
+var ErrInvalidOption = errors.New("invalid option value")
+
+// ErrPaymentQRCurrency is returned when a payment qr code is set on a document whose currency is not EUR
+var ErrPaymentQRCurrency = errors.New("payment qr code requires EUR currency")
+
+// ErrPaymentQRAmount is returned when a payment qr code amount is not positive, ex on credit notes
+var ErrPaymentQRAmount = errors.New("payment qr code amount must be positive")
+
+// ErrPaymentQRName is returned when a payment qr code beneficiary name is empty or longer than 70 characters
+var ErrPaymentQRName = errors.New("payment qr code beneficiary name must be 1 to 70 characters")
+
+// ErrAttachmentTooLarge is returned when an attachment content exceeds Options.MaxAttachmentSize
+var ErrAttachmentTooLarge = errors.New("attachment exceeds max size")
+
+// ErrMissingCurrencyCode is returned when exporting an electronic invoice without Options.CurrencyCode
+var ErrMissingCurrencyCode = errors.New("currency code is required for export")
+
+// ErrUnsupportedFacturXProfile is returned when a Factur-X profile is not BASIC nor EN16931
+var ErrUnsupportedFacturXProfile = errors.New("unsupported factur-x profile")
+
+// ErrRasterizerUnavailable is returned by RenderPNG when library is built without a rasterizer
+var ErrRasterizerUnavailable = errors.New("png rasterizer unavailable, build with pdftoppm tag")
+
+// ItemError define a validation error on a document item
+type ItemError struct {
+	Index int    // Index of item in document items
+	Field string // Name of item field
+	Err   error
+}
+
+// Error implement error interface
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("item %d: %s: %s", e.Index, e.Field, e.Err)
+}
+
+// Unwrap return underlying error
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// DocumentError define an error on a document of a batch, see BuildBatch
+type DocumentError struct {
+	Index int // Index of document in batch
+	Err   error
+}
+
+// Error implement error interface
+func (e *DocumentError) Error() string {
+	return fmt.Sprintf("document %d: %s", e.Index, e.Err)
+}
+
+// Unwrap return underlying error
+func (e *DocumentError) Unwrap() error {
+	return e.Err
+}
+
+// FieldError define a validation error on a document field
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+// Error implement error interface
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Err)
+}
+
+// Unwrap return the underlying error
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,371 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/shopspring/decimal"
+)
+
+// FacturXFilename define the name of the CII xml file embedded in Factur-X pdf
+const FacturXFilename string = "factur-x.xml"
+
+// Factur-X / ZUGFeRD guideline identifiers by profile
+var facturXGuidelines = map[string]string{
+	FacturXProfileBasic:   "urn:cen.eu:en16931:2017#compliant#urn:factur-x.eu:1p0:basic",
+	FacturXProfileEN16931: "urn:cen.eu:en16931:2017",
+}
+
+// Factur-X xmp conformance levels by profile
+var facturXConformanceLevels = map[string]string{
+	FacturXProfileBasic:   "BASIC",
+	FacturXProfileEN16931: "EN 16931",
+}
+
+// UN/CEFACT Cross Industry Invoice namespaces
+const (
+	ciiRSMNamespace = "urn:un:unece:uncefact:data:standard:CrossIndustryInvoice:100"
+	ciiRAMNamespace = "urn:un:unece:uncefact:data:standard:ReusableAggregateBusinessInformationEntity:100"
+	ciiUDTNamespace = "urn:un:unece:uncefact:data:standard:UnqualifiedDataType:100"
+)
+
+// ciiInvoice define the CII CrossIndustryInvoice root, elements being in schema order
+type ciiInvoice struct {
+	XMLName     xml.Name            `xml:"rsm:CrossIndustryInvoice"`
+	XmlnsRSM    string              `xml:"xmlns:rsm,attr"`
+	XmlnsRAM    string              `xml:"xmlns:ram,attr"`
+	XmlnsUDT    string              `xml:"xmlns:udt,attr"`
+	GuidelineID string              `xml:"rsm:ExchangedDocumentContext>ram:GuidelineSpecifiedDocumentContextParameter>ram:ID"`
+	Document    ciiDocument         `xml:"rsm:ExchangedDocument"`
+	Transaction ciiTradeTransaction `xml:"rsm:SupplyChainTradeTransaction"`
+}
+
+type ciiDocument struct {
+	ID            string   `xml:"ram:ID"`
+	TypeCode      string   `xml:"ram:TypeCode"`
+	IssueDateTime ciiDate  `xml:"ram:IssueDateTime>udt:DateTimeString"`
+	Notes         []string `xml:"ram:IncludedNote>ram:Content"`
+}
+
+type ciiDate struct {
+	Format string `xml:"format,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type ciiAmount struct {
+	CurrencyID string `xml:"currencyID,attr,omitempty"`
+	Value      string `xml:",chardata"`
+}
+
+type ciiQuantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ciiTradeTransaction struct {
+	Lines      []ciiLineItem       `xml:"ram:IncludedSupplyChainTradeLineItem"`
+	Agreement  ciiHeaderAgreement  `xml:"ram:ApplicableHeaderTradeAgreement"`
+	Delivery   struct{}            `xml:"ram:ApplicableHeaderTradeDelivery"`
+	Settlement ciiHeaderSettlement `xml:"ram:ApplicableHeaderTradeSettlement"`
+}
+
+type ciiLineItem struct {
+	LineID      string            `xml:"ram:AssociatedDocumentLineDocument>ram:LineID"`
+	Name        string            `xml:"ram:SpecifiedTradeProduct>ram:Name"`
+	Description string            `xml:"ram:SpecifiedTradeProduct>ram:Description,omitempty"`
+	NetPrice    ciiAmount         `xml:"ram:SpecifiedLineTradeAgreement>ram:NetPriceProductTradePrice>ram:ChargeAmount"`
+	Quantity    ciiQuantity       `xml:"ram:SpecifiedLineTradeDelivery>ram:BilledQuantity"`
+	Settlement  ciiLineSettlement `xml:"ram:SpecifiedLineTradeSettlement"`
+}
+
+type ciiLineSettlement struct {
+	Tax              ciiTradeTax          `xml:"ram:ApplicableTradeTax"`
+	AllowanceCharges []ciiAllowanceCharge `xml:"ram:SpecifiedTradeAllowanceCharge"`
+	LineTotalAmount  ciiAmount            `xml:"ram:SpecifiedTradeSettlementLineMonetarySummation>ram:LineTotalAmount"`
+}
+
+type ciiTradeTax struct {
+	CalculatedAmount *ciiAmount `xml:"ram:CalculatedAmount,omitempty"`
+	TypeCode         string     `xml:"ram:TypeCode"`
+	BasisAmount      *ciiAmount `xml:"ram:BasisAmount,omitempty"`
+	CategoryCode     string     `xml:"ram:CategoryCode"`
+	Percent          string     `xml:"ram:RateApplicablePercent,omitempty"`
+}
+
+type ciiAllowanceCharge struct {
+	ChargeIndicator bool         `xml:"ram:ChargeIndicator>udt:Indicator"`
+	ActualAmount    ciiAmount    `xml:"ram:ActualAmount"`
+	Reason          string       `xml:"ram:Reason,omitempty"`
+	CategoryTax     *ciiTradeTax `xml:"ram:CategoryTradeTax,omitempty"`
+}
+
+type ciiHeaderAgreement struct {
+	Seller ciiParty `xml:"ram:SellerTradeParty"`
+	Buyer  ciiParty `xml:"ram:BuyerTradeParty"`
+}
+
+type ciiParty struct {
+	Name            string              `xml:"ram:Name"`
+	Address         *ciiAddress         `xml:"ram:PostalTradeAddress,omitempty"`
+	TaxRegistration *ciiTaxRegistration `xml:"ram:SpecifiedTaxRegistration>ram:ID,omitempty"`
+}
+
+type ciiTaxRegistration struct {
+	SchemeID string `xml:"schemeID,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ciiAddress struct {
+	PostcodeCode string `xml:"ram:PostcodeCode,omitempty"`
+	LineOne      string `xml:"ram:LineOne,omitempty"`
+	LineTwo      string `xml:"ram:LineTwo,omitempty"`
+	CityName     string `xml:"ram:CityName,omitempty"`
+	CountryID    string `xml:"ram:CountryID,omitempty"`
+}
+
+type ciiHeaderSettlement struct {
+	Currency         string               `xml:"ram:InvoiceCurrencyCode"`
+	Taxes            []ciiTradeTax        `xml:"ram:ApplicableTradeTax"`
+	AllowanceCharges []ciiAllowanceCharge `xml:"ram:SpecifiedTradeAllowanceCharge"`
+	DueDate          *ciiDate             `xml:"ram:SpecifiedTradePaymentTerms>ram:DueDateDateTime>udt:DateTimeString,omitempty"`
+	Summation        ciiSummation         `xml:"ram:SpecifiedTradeSettlementHeaderMonetarySummation"`
+}
+
+type ciiSummation struct {
+	LineTotalAmount      ciiAmount  `xml:"ram:LineTotalAmount"`
+	ChargeTotalAmount    ciiAmount  `xml:"ram:ChargeTotalAmount"`
+	AllowanceTotalAmount ciiAmount  `xml:"ram:AllowanceTotalAmount"`
+	TaxBasisTotalAmount  ciiAmount  `xml:"ram:TaxBasisTotalAmount"`
+	TaxTotalAmount       ciiAmount  `xml:"ram:TaxTotalAmount"`
+	GrandTotalAmount     ciiAmount  `xml:"ram:GrandTotalAmount"`
+	TotalPrepaidAmount   *ciiAmount `xml:"ram:TotalPrepaidAmount,omitempty"`
+	DuePayableAmount     ciiAmount  `xml:"ram:DuePayableAmount"`
+}
+
+// newCIITradeTax return CII trade tax category of tax
+func newCIITradeTax(tax *Tax) ciiTradeTax {
+	id, percent := vatCategory(tax)
+	return ciiTradeTax{TypeCode: "VAT", CategoryCode: id, Percent: percent}
+}
+
+func newCIIParty(contact *Contact) ciiParty {
+	party := ciiParty{Name: contact.Name}
+	if contact.Address != nil {
+		party.Address = &ciiAddress{
+			PostcodeCode: contact.Address.PostalCode,
+			LineOne:      contact.Address.Address,
+			LineTwo:      contact.Address.Address2,
+			CityName:     contact.Address.City,
+			CountryID:    strings.ToUpper(contact.Address.CountryCode),
+		}
+	}
+
+	if len(contact.TaxID) > 0 {
+		party.TaxRegistration = &ciiTaxRegistration{SchemeID: "VA", Value: normalizeTaxID(contact.TaxID)}
+	}
+
+	return party
+}
+
+// ExportCII serialize document as an UN/CEFACT Cross Industry Invoice xml
+// following Factur-X / ZUGFeRD profile (FacturXProfileBasic or FacturXProfileEN16931)
+func (d *Document) ExportCII(profile string) ([]byte, error) {
+	guideline, ok := facturXGuidelines[profile]
+	if !ok {
+		return nil, ErrUnsupportedFacturXProfile
+	}
+
+	totals, err := d.ComputeTotals()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	currency, err := d.exportCurrency()
+	if err != nil {
+		return nil, err
+	}
+
+	precision := int32(d.Options.CurrencyPrecision)
+	amount := func(value decimal.Decimal) ciiAmount {
+		return ciiAmount{Value: value.StringFixed(precision)}
+	}
+
+	typeCode := "380"
+	if d.Type == CreditNote {
+		typeCode = "381"
+	}
+
+	invoice := ciiInvoice{
+		XmlnsRSM:    ciiRSMNamespace,
+		XmlnsRAM:    ciiRAMNamespace,
+		XmlnsUDT:    ciiUDTNamespace,
+		GuidelineID: guideline,
+		Document: ciiDocument{
+			ID:            d.Ref,
+			TypeCode:      typeCode,
+			IssueDateTime: ciiDate{Format: "102", Value: d.dateTime().Format("20060102")},
+		},
+	}
+
+	if len(d.Description) > 0 {
+		invoice.Document.Notes = append(invoice.Document.Notes, d.Description)
+	}
+
+	// Lines, amounts without tax
+	for index, item := range d.convertedItems(d.activeItems()) {
+		taxes := d.itemTaxes(item)
+		price, lineAmount, lineTotal := d.netLine(item)
+
+		line := ciiLineItem{
+			LineID:      fmt.Sprintf("%d", index+1),
+			Name:        item.Name,
+			Description: item.Description,
+			NetPrice:    amount(price),
+			Quantity:    ciiQuantity{UnitCode: "C62", Value: item.quantity().String()},
+		}
+
+		if len(taxes) > 0 {
+			line.Settlement.Tax = newCIITradeTax(taxes[0])
+		} else {
+			line.Settlement.Tax = newCIITradeTax(nil)
+		}
+
+		if item.Discount != nil {
+			line.Settlement.AllowanceCharges = append(line.Settlement.AllowanceCharges, ciiAllowanceCharge{
+				ChargeIndicator: false,
+				ActualAmount:    amount(lineAmount.Sub(lineTotal)),
+				Reason:          item.Discount.Label,
+			})
+		}
+
+		line.Settlement.LineTotalAmount = amount(lineTotal)
+		invoice.Transaction.Lines = append(invoice.Transaction.Lines, line)
+	}
+
+	// Parties
+	invoice.Transaction.Agreement = ciiHeaderAgreement{
+		Seller: newCIIParty(d.Company),
+		Buyer:  newCIIParty(d.Customer),
+	}
+
+	// Settlement
+	settlement := &invoice.Transaction.Settlement
+	settlement.Currency = currency
+
+	for _, line := range d.taxSummaryLines() {
+		tax := newCIITradeTax(line.rate)
+		calculated := amount(line.tax)
+		basis := amount(line.net)
+		tax.CalculatedAmount = &calculated
+		tax.BasisAmount = &basis
+		settlement.Taxes = append(settlement.Taxes, tax)
+	}
+
+	if d.Discount != nil {
+		tax := newCIITradeTax(d.discountTax())
+		settlement.AllowanceCharges = append(settlement.AllowanceCharges, ciiAllowanceCharge{
+			ChargeIndicator: false,
+			ActualAmount:    amount(totals.TotalDiscount),
+			Reason:          d.Discount.Label,
+			CategoryTax:     &tax,
+		})
+	}
+
+	if d.Shipping != nil {
+		reason := d.Shipping.Label
+		if len(reason) == 0 {
+			reason = d.Options.TextTotalShipping
+		}
+
+		tax := newCIITradeTax(d.Shipping.Tax)
+		settlement.AllowanceCharges = append(settlement.AllowanceCharges, ciiAllowanceCharge{
+			ChargeIndicator: true,
+			ActualAmount:    amount(totals.TotalShipping),
+			Reason:          reason,
+			CategoryTax:     &tax,
+		})
+	}
+
+	if dueDate, ok := d.dueDateTime(); ok {
+		settlement.DueDate = &ciiDate{Format: "102", Value: dueDate.Format("20060102")}
+	}
+
+	taxExclusive := totals.TotalGross.Sub(totals.TotalTax)
+	taxTotal := amount(totals.TotalTax)
+	taxTotal.CurrencyID = currency
+	settlement.Summation = ciiSummation{
+		LineTotalAmount:      amount(taxExclusive.Add(totals.TotalDiscount).Sub(totals.TotalShipping)),
+		ChargeTotalAmount:    amount(totals.TotalShipping),
+		AllowanceTotalAmount: amount(totals.TotalDiscount),
+		TaxBasisTotalAmount:  amount(taxExclusive),
+		TaxTotalAmount:       taxTotal,
+		GrandTotalAmount:     amount(totals.TotalGross),
+		DuePayableAmount:     amount(exportPayable(totals)),
+	}
+
+	if d.Withholding != nil || len(d.Payments) > 0 {
+		prepaid := amount(exportPrepaid(totals))
+		settlement.Summation.TotalPrepaidAmount = &prepaid
+	}
+
+	output, err := xml.MarshalIndent(invoice, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), output...), nil
+}
+
+// facturXMetadata return xmp packet declaring the embedded Factur-X invoice
+func facturXMetadata(profile string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xpacket begin="" id="W5M0MpCehiHzreSzNTczkc9d"?>` + "\n")
+	buf.WriteString(`<x:xmpmeta xmlns:x="adobe:ns:meta/">` + "\n")
+	buf.WriteString(`<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">` + "\n")
+	buf.WriteString(`<rdf:Description rdf:about="" xmlns:fx="urn:factur-x:pdfa:CrossIndustryDocument:invoice:1p0#">` + "\n")
+	buf.WriteString("<fx:DocumentType>INVOICE</fx:DocumentType>\n")
+	buf.WriteString("<fx:DocumentFileName>" + FacturXFilename + "</fx:DocumentFileName>\n")
+	buf.WriteString("<fx:Version>1.0</fx:Version>\n")
+	buf.WriteString("<fx:ConformanceLevel>" + facturXConformanceLevels[profile] + "</fx:ConformanceLevel>\n")
+	buf.WriteString("</rdf:Description>\n")
+	buf.WriteString("</rdf:RDF>\n")
+	buf.WriteString("</x:xmpmeta>\n")
+	buf.WriteString(`<?xpacket end="w"?>`)
+
+	return buf.Bytes()
+}
+
+// BuildWithCII build pdf document with its CII xml embedded as factur-x.xml,
+// for profile FacturXProfileBasic or FacturXProfileEN16931. Xmp metadata
+// describing the embedded invoice replace the XMPMetadata option packet.
+//
+// The result is a plain pdf with an embedded CII invoice, not a Factur-X /
+// ZUGFeRD file: gofpdf can neither write the /AFRelationship of the attachment
+// nor produce PDF/A-3 output, so Factur-X validators reject it.
+func (d *Document) BuildWithCII(profile string) (*gofpdf.Fpdf, error) {
+	xmlContent, err := d.ExportCII(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf, err := d.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	pdf.SetAttachments(append(d.pdfAttachments(), gofpdf.Attachment{
+		Content:     xmlContent,
+		Filename:    FacturXFilename,
+		Description: "Cross Industry Invoice",
+	}))
+	pdf.SetXmpMetadata(facturXMetadata(profile))
+
+	return pdf, pdf.Error()
+}
@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Font define a custom TTF font family, used instead of Helvetica for all
+// document texts. Texts are rendered as UTF-8, allowing non-Latin scripts.
+type Font struct {
+	Family  string `json:"family,omitempty" validate:"required"`
+	Regular []byte `json:"regular,omitempty" validate:"required"` // TTF bytes
+	Bold    []byte `json:"bold,omitempty"`                        // TTF bytes, Regular is used if empty
+	Italic  []byte `json:"italic,omitempty"`                      // TTF bytes, Regular is used if empty
+}
+
+// register font styles in pdf, missing styles fallback to regular
+func (f *Font) register(pdf *gofpdf.Fpdf) {
+	bold := f.Bold
+	if len(bold) == 0 {
+		bold = f.Regular
+	}
+
+	italic := f.Italic
+	if len(italic) == 0 {
+		italic = f.Regular
+	}
+
+	pdf.AddUTF8FontFromBytes(f.Family, "", f.Regular)
+	pdf.AddUTF8FontFromBytes(f.Family, "B", bold)
+	pdf.AddUTF8FontFromBytes(f.Family, "I", italic)
+	pdf.AddUTF8FontFromBytes(f.Family, "BI", bold)
+}
+
+// fontFamily return the font family used for document texts
+func (o *Options) fontFamily() string {
+	if o.Font != nil {
+		return o.Font.Family
+	}
+
+	return "Helvetica"
+}
+
+// encodeString encode str for document font, custom fonts being UTF-8 they
+// need no translation
+func (o *Options) encodeString(str string) string {
+	if o.Font != nil {
+		return str
+	}
+
+	return encodeString(str)
+}
@@ -1,13 +1,14 @@
 // Package generator allows you to easily generate invoices, delivery notes and quotations in GoLang.
 package generator
 
-import (
-	"github.com/creasty/defaults"
-)
-
-// New return a new documents with provided types and defaults
+// New return a new documents with provided types and defaults, unset options
+// being filled with defaults (english titles, currency format). Options may be nil.
 func New(docType string, options *Options) (*Document, error) {
-	if err := defaults.Set(options); err != nil {
+	if options == nil {
+		options = &Options{}
+	}
+
+	if err := options.setDefaults(); err != nil {
 		return nil, err
 	}
 
@@ -1,8 +1,17 @@
 package generator
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"io/ioutil"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/shopspring/decimal"
 )
 
 func TestNew(t *testing.T) {
@@ -34,7 +43,7 @@ func TestNew(t *testing.T) {
 
 	doc.SetCompany(&Contact{
 		Name: "Test Company",
-		Logo: &logoBytes,
+		Logo: &Logo{Bytes: logoBytes, MimeType: "image/png"},
 		Address: &Address{
 			Address:    "89 Rue de Brest",
 			Address2:   "Appartement 2",
@@ -118,3 +127,1149 @@ func TestNew(t *testing.T) {
 		t.Errorf(err.Error())
 	}
 }
+
+func TestNewDocumentFromJSON(t *testing.T) {
+	doc, err := NewDocumentFromJSON([]byte(`{
+		"type": "INVOICE",
+		"ref": "testref",
+		"company": {"name": "Test Company"},
+		"customer": {"name": "Test Customer"},
+		"items": [
+			{"name": "Number", "unit_cost": 12.345678901234567890, "quantity": 3, "tax": {"percent": 20}},
+			{"name": "String", "unit_cost": "10.10", "quantity": "1", "discount": {"amount": "1.10"}}
+		],
+		"discount": {"percent": 10},
+		"shipping": {"amount": 5}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.Items[0].UnitCost != "12.345678901234567890" || doc.Items[0].Quantity != "3" || doc.Items[0].Tax.Percent != "20" {
+		t.Errorf("number amounts not kept as written: %+v", doc.Items[0])
+	}
+
+	if doc.Items[1].UnitCost != "10.10" || doc.Items[1].Discount.Amount != "1.10" {
+		t.Errorf("string amounts not kept as written: %+v", doc.Items[1])
+	}
+
+	if doc.Discount.Percent != "10" || doc.Shipping.Amount != "5" {
+		t.Errorf("document amounts not decoded: %+v %+v", doc.Discount, doc.Shipping)
+	}
+
+	if doc.Options.TextTypeInvoice != "INVOICE" {
+		t.Errorf("options defaults not set")
+	}
+
+	// Round trip
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := NewDocumentFromJSON(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reencoded, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(encoded, reencoded) {
+		t.Errorf("round trip mismatch:\n%s\n%s", encoded, reencoded)
+	}
+
+	totals, err := doc.ComputeTotals()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decodedTotals, err := decoded.ComputeTotals()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !totals.TotalGross.Equal(decodedTotals.TotalGross) {
+		t.Errorf("round trip totals mismatch: %s != %s", totals.TotalGross, decodedTotals.TotalGross)
+	}
+
+	if _, err := doc.Build(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNewDocumentFromJSONInvalidAmount(t *testing.T) {
+	if _, err := NewDocumentFromJSON([]byte(`{"items": [{"name": "Bad", "unit_cost": true}]}`)); err == nil {
+		t.Error("expected error on invalid amount")
+	}
+}
+
+// newTestDocument return a document with required fields set and items
+func newTestDocument(docType string, items ...*Item) *Document {
+	doc, _ := New(docType, &Options{})
+	doc.SetRef("testref")
+	doc.SetCompany(&Contact{Name: "Test Company"})
+	doc.SetCustomer(&Contact{Name: "Test Customer"})
+	for _, item := range items {
+		doc.AppendItem(item)
+	}
+	return doc
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   *Document
+		index int
+		field string
+		err   error
+	}{
+		{
+			name: "valid",
+			doc:  newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "2", Tax: &Tax{Percent: "20"}}),
+		},
+		{
+			name: "no items",
+			doc:  newTestDocument(Invoice),
+			err:  ErrNoItems,
+		},
+		{
+			name:  "negative unit cost",
+			doc:   newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1"}, &Item{Name: "Test", UnitCost: "-10", Quantity: "1"}),
+			index: 1,
+			field: "UnitCost",
+			err:   ErrNegativeUnitCost,
+		},
+		{
+			name:  "negative quantity",
+			doc:   newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "-1"}),
+			field: "Quantity",
+			err:   ErrNegativeQuantity,
+		},
+		{
+			name: "credit note negative amounts",
+			doc:  newTestDocument(CreditNote, &Item{Name: "Test", UnitCost: "-10", Quantity: "-1"}),
+		},
+		{
+			name:  "tax percent above 100",
+			doc:   newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1", Tax: &Tax{Percent: "120"}}),
+			field: "Tax",
+			err:   ErrPercentOutOfRange,
+		},
+		{
+			name:  "negative compound tax percent",
+			doc:   newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1", Taxes: []*Tax{{Percent: "5"}, {Percent: "-1", Compound: true}}}),
+			field: "Taxes[1]",
+			err:   ErrPercentOutOfRange,
+		},
+		{
+			name:  "negative discount percent",
+			doc:   newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1", Discount: &Discount{Percent: "-5"}}),
+			field: "Discount",
+			err:   ErrPercentOutOfRange,
+		},
+		{
+			name:  "discount above 100",
+			doc:   newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1", Discount: &Discount{Percent: "150"}}),
+			field: "Discount",
+			err:   ErrDiscountExceedsTotal,
+		},
+		{
+			name:  "document discount above 100",
+			doc:   newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1"}).SetDiscount(&Discount{Percent: "101"}),
+			index: -1,
+			field: "Discount",
+			err:   ErrPercentOutOfRange,
+		},
+		{
+			name:  "default tax above 100",
+			doc:   newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1"}).SetDefaultTax(&Tax{Percent: "200"}),
+			index: -1,
+			field: "DefaultTax",
+			err:   ErrPercentOutOfRange,
+		},
+	}
+
+	for _, test := range tests {
+		err := test.doc.Validate()
+
+		switch {
+		case test.err == nil:
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", test.name, err)
+			}
+		case len(test.field) == 0:
+			if err != test.err {
+				t.Errorf("%s: expected %v, got %v", test.name, test.err, err)
+			}
+		case test.index < 0:
+			fieldErr, ok := err.(*FieldError)
+			if !ok || fieldErr.Field != test.field || fieldErr.Err != test.err {
+				t.Errorf("%s: expected %s: %v, got %v", test.name, test.field, test.err, err)
+			}
+		default:
+			itemErr, ok := err.(*ItemError)
+			if !ok || itemErr.Index != test.index || itemErr.Field != test.field || itemErr.Err != test.err {
+				t.Errorf("%s: expected item %d %s: %v, got %v", test.name, test.index, test.field, test.err, err)
+			}
+		}
+	}
+}
+
+func TestEncodeString(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"Total: 10 €", "Total: 10 \x80"},
+		{"“Quoted” ‘single’", "\x93Quoted\x94 \x91single\x92"},
+		{"10–20 — end", "10\x9620 \x97 end"},
+		{"Café déjà", "Caf\xe9 d\xe9j\xe0"},
+		{"Łódź − 5 → ₹", "L\xf3dz - 5 -> Rs"},
+		{"日本", "??"},
+	}
+
+	for _, test := range tests {
+		if out := encodeString(test.in); out != test.out {
+			t.Errorf("encodeString(%q) = %q, expected %q", test.in, out, test.out)
+		}
+
+		// Encoding twice keep CP1252 bytes
+		if out := encodeString(encodeString(test.in)); out != test.out {
+			t.Errorf("encodeString twice (%q) = %q, expected %q", test.in, out, test.out)
+		}
+	}
+}
+
+func TestCurrencySymbolPosition(t *testing.T) {
+	tests := []struct {
+		options *Options
+		value   string
+		out     string
+	}{
+		{&Options{CurrencySymbol: "$", CurrencyThousand: ","}, "1234.56", "$1,234.56"},
+		{&Options{CurrencySymbolAfter: true, CurrencySymbolSeparator: " ", CurrencyDecimal: ",", CurrencyThousand: "."}, "1234.56", "1.234,56 €"},
+		{&Options{CurrencySymbolAfter: true, CurrencySymbolSeparator: " ", CurrencyDecimal: ",", CurrencyThousand: "."}, "-1234.56", "-1.234,56 €"},
+		{&Options{CurrencySymbolAfter: true, CurrencySymbolSeparator: " ", CurrencyGrouping: []int{3, 2}, CurrencySymbol: "Rs"}, "1234567.8", "12,34,567.80 Rs"},
+		{&Options{}, "1234.56", "€ 1,234.56"},
+	}
+
+	for _, test := range tests {
+		doc, _ := New(Invoice, test.options)
+		out := doc.Options.moneyFormatter().FormatMoneyDecimal(decimal.RequireFromString(test.value))
+		if expected := encodeString(test.out); out != expected {
+			t.Errorf("format %s = %q, expected %q", test.value, out, expected)
+		}
+	}
+}
+
+func TestDocumentDiscountExceedsTotal(t *testing.T) {
+	tests := []struct {
+		name     string
+		discount *Discount
+	}{
+		{"amount above total", &Discount{Amount: "150"}},
+		{"percent of 100", &Discount{Percent: "100"}},
+	}
+
+	for _, test := range tests {
+		doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "50", Quantity: "2", Tax: &Tax{Percent: "20"}}).SetDiscount(test.discount)
+
+		totals, err := doc.ComputeTotals()
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", test.name, err)
+		}
+
+		if !totals.TotalDiscount.Equal(decimal.NewFromFloat(100)) || !totals.TotalTax.IsZero() || !totals.TotalGross.IsZero() {
+			t.Errorf("%s: discount not clamped to total: %+v", test.name, totals)
+		}
+
+		if _, err := doc.Build(); err != nil {
+			t.Errorf("%s: unexpected build error %v", test.name, err)
+		}
+	}
+
+	// Strict discounts
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "50", Quantity: "2"}).SetDiscount(&Discount{Amount: "150"})
+	doc.Options.StrictDiscounts = true
+
+	fieldErr, ok := doc.Validate().(*FieldError)
+	if !ok || fieldErr.Field != "Discount" || fieldErr.Err != ErrDiscountExceedsTotal {
+		t.Errorf("expected strict discount error, got %v", fieldErr)
+	}
+}
+
+func TestZeroSubtotal(t *testing.T) {
+	doc := newTestDocument(Invoice, &Item{Name: "Free", UnitCost: "0", Quantity: "1", Tax: &Tax{Percent: "20"}}).SetDiscount(&Discount{Amount: "10"})
+
+	totals, err := doc.ComputeTotals()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if !totals.TotalDiscount.IsZero() || !totals.TotalGross.IsZero() || !totals.AmountDue.IsZero() {
+		t.Errorf("expected zero totals, got %+v", totals)
+	}
+
+	if _, err := doc.Build(); err != nil {
+		t.Errorf("unexpected build error %v", err)
+	}
+}
+
+func TestConcurrentBuild(t *testing.T) {
+	logoBytes, _ := ioutil.ReadFile("./example_logo.png")
+
+	doc := newTestDocument(Invoice,
+		&Item{Name: "Untaxed", UnitCost: "99.876", Quantity: "2"},
+		&Item{Name: "Taxed", UnitCost: "10", Quantity: "3", Tax: &Tax{Percent: "5.5"}, Discount: &Discount{Percent: "10"}},
+	)
+	doc.SetDate("02/03/2021").SetDefaultTax(&Tax{Percent: "20"})
+	doc.SetHeader(&HeaderFooter{Title: "Test Company", Lines: []string{"Paris"}, Logo: &Logo{Bytes: logoBytes, MimeType: "image/png"}, Divider: true})
+	doc.SetFooter(&HeaderFooter{Text: "Footer", Pagination: true})
+	doc.Company.Logo = &Logo{Bytes: logoBytes, MimeType: "image/png"}
+
+	build := func() ([]byte, error) {
+		pdf, err := doc.Build()
+		if err != nil {
+			return nil, err
+		}
+
+		pdf.SetCatalogSort(true)
+		date := time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC)
+		pdf.SetCreationDate(date)
+		pdf.SetModificationDate(date)
+		output := &bytes.Buffer{}
+		err = pdf.Output(output)
+		return output.Bytes(), err
+	}
+
+	expected, err := build()
+	if err != nil {
+		t.Fatalf("unexpected build error %v", err)
+	}
+
+	var wg sync.WaitGroup
+	outputs := make([][]byte, 2)
+	errs := make([]error, 2)
+	for i := range outputs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			outputs[i], errs[i] = build()
+		}(i)
+	}
+	wg.Wait()
+
+	for i, output := range outputs {
+		if errs[i] != nil {
+			t.Fatalf("goroutine %d: unexpected build error %v", i, errs[i])
+		}
+
+		if !bytes.Equal(output, expected) {
+			t.Errorf("goroutine %d: output differs from sequential build", i)
+		}
+	}
+
+	if doc.Items[0].Tax != nil {
+		t.Errorf("build modified item tax: %+v", doc.Items[0].Tax)
+	}
+
+	if doc.Header.FontSize != 0 || doc.Company.Logo.MaxWidth != 0 {
+		t.Errorf("build set header or logo defaults")
+	}
+}
+
+func TestPricesIncludeTax(t *testing.T) {
+	item := &Item{Name: "Gross", UnitCost: "60", Quantity: "2", Tax: &Tax{Percent: "20"}}
+	doc := newTestDocument(Invoice, item)
+	doc.Options.PricesIncludeTax = true
+
+	if !item.netWithDiscount(true).Equal(decimal.NewFromFloat(100)) || !item.taxWithDiscount(true).Equal(decimal.NewFromFloat(20)) {
+		t.Errorf("unexpected item net %s and tax %s", item.netWithDiscount(true), item.taxWithDiscount(true))
+	}
+
+	totals, err := doc.ComputeTotals()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if !totals.TotalNet.Equal(decimal.NewFromFloat(100)) || !totals.TotalTax.Equal(decimal.NewFromFloat(20)) || !totals.TotalGross.Equal(decimal.NewFromFloat(120)) {
+		t.Errorf("expected tax extracted from gross prices, got %+v", totals)
+	}
+
+	if warning := doc.CheckRounding(); warning != nil {
+		t.Errorf("unexpected rounding warning %+v", warning)
+	}
+
+	if _, err := doc.Build(); err != nil {
+		t.Errorf("unexpected build error %v", err)
+	}
+}
+
+func TestColumnAlign(t *testing.T) {
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.Options.ItemColumns = []ItemColumn{
+		{Name: ItemColumnName, Width: 2},
+		{Name: ItemColumnQuantity, Width: 1, Align: "C"},
+		{Name: ItemColumnTotalTTC, Width: 1},
+	}
+
+	aligns := map[string]string{}
+	for _, column := range doc.ColumnLayout() {
+		aligns[column.Name] = column.Align
+	}
+
+	if aligns[ItemColumnName] != "L" || aligns[ItemColumnQuantity] != "C" || aligns[ItemColumnTotalTTC] != "R" {
+		t.Errorf("unexpected columns alignment %v", aligns)
+	}
+
+	doc.Options.ItemColumns[1].Align = "X"
+	if err := doc.Validate(); err == nil {
+		t.Errorf("expected invalid alignment error")
+	}
+}
+
+func TestClampPercents(t *testing.T) {
+	if err := (&Tax{Percent: "twenty"}).Validate(); err != ErrInvalidNumber {
+		t.Errorf("expected invalid number error, got %v", err)
+	}
+
+	if err := (&Discount{Percent: "120"}).Validate(); err != ErrPercentOutOfRange {
+		t.Errorf("expected percent out of range error, got %v", err)
+	}
+
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "120"}, Discount: &Discount{Percent: "-10"}})
+	doc.Options.ClampPercents = true
+
+	if err := doc.Validate(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	totals, err := doc.ComputeTotals()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if !totals.TotalNet.Equal(decimal.NewFromFloat(100)) || !totals.TotalTax.Equal(decimal.NewFromFloat(100)) {
+		t.Errorf("expected clamped percents, got %+v", totals)
+	}
+}
+
+func TestNotesPosition(t *testing.T) {
+	totalsY := map[string]float64{}
+	for _, position := range []string{NotesPositionBesideTotals, NotesPositionBelowTotals, NotesPositionAboveTotals} {
+		doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+		doc.SetNotes("Line one\nLine two\nLine three")
+		doc.Options.NotesPosition = position
+
+		_, layout, err := doc.BuildWithLayout()
+		if err != nil {
+			t.Fatalf("%s: unexpected build error %v", position, err)
+		}
+
+		totalsY[position] = layout.Totals.Y
+	}
+
+	if totalsY[NotesPositionBelowTotals] != totalsY[NotesPositionBesideTotals] {
+		t.Errorf("expected totals position unchanged by notes below totals, got %v", totalsY)
+	}
+
+	if totalsY[NotesPositionAboveTotals] <= totalsY[NotesPositionBesideTotals] {
+		t.Errorf("expected totals below notes, got %v", totalsY)
+	}
+
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.Options.NotesFullWidth = true
+	doc.Options.NotesPosition = NotesPositionBesideTotals
+	if !doc.notesBesideTotals() {
+		t.Errorf("expected notes beside totals to override NotesFullWidth")
+	}
+
+	doc.Options.NotesPosition = "below"
+	fieldErr, ok := doc.Validate().(*FieldError)
+	if !ok || fieldErr.Field != "Options.NotesPosition" || fieldErr.Err != ErrInvalidOption {
+		t.Errorf("expected invalid notes position error, got %v", doc.Validate())
+	}
+}
+
+func TestStrictTaxIDs(t *testing.T) {
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.Company.TaxID = "FR 40 303 265 045"
+	doc.Customer.TaxID = "DE12345"
+
+	if err := doc.Validate(); err != nil {
+		t.Errorf("unexpected error without strict tax ids %v", err)
+	}
+
+	doc.Options.StrictTaxIDs = true
+	fieldErr, ok := doc.Validate().(*FieldError)
+	if !ok || fieldErr.Field != "Customer.TaxID" || fieldErr.Err != ErrInvalidTaxID {
+		t.Errorf("expected customer tax id error, got %v", fieldErr)
+	}
+
+	doc.Customer.TaxID = "US 12-3456789"
+	if err := doc.Validate(); err != nil {
+		t.Errorf("unexpected error on non EU tax id %v", err)
+	}
+
+	if _, err := doc.Build(); err != nil {
+		t.Errorf("unexpected build error %v", err)
+	}
+}
+
+func TestEstimatePageCount(t *testing.T) {
+	doc := newTestDocument(Invoice)
+	for i := 0; i < 60; i++ {
+		doc.AppendItem(&Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	}
+
+	count, err := doc.EstimatePageCount()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	pdf, _ := doc.Build()
+	if count < 2 || count != pdf.PageCount() {
+		t.Errorf("expected %d pages, got %d", pdf.PageCount(), count)
+	}
+
+	if _, err := newTestDocument(Invoice).EstimatePageCount(); err != ErrNoItems {
+		t.Errorf("expected no items error, got %v", err)
+	}
+}
+
+func TestBuildBatch(t *testing.T) {
+	first := newTestDocument(Invoice, &Item{Name: "First", UnitCost: "10", Quantity: "1"})
+	first.SetFooter(&HeaderFooter{Text: "First footer", Pagination: true})
+	second := newTestDocument(CreditNote, &Item{Name: "Second", UnitCost: "20", Quantity: "1"})
+
+	pdf, err := BuildBatch([]*Document{first, second})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if pdf.PageCount() != 2 {
+		t.Errorf("expected 2 pages, got %d", pdf.PageCount())
+	}
+
+	if err := pdf.Output(&bytes.Buffer{}); err != nil {
+		t.Errorf("unexpected output error %v", err)
+	}
+
+	_, err = BuildBatch([]*Document{first, newTestDocument(Invoice)})
+	docErr, ok := err.(*DocumentError)
+	if !ok || docErr.Index != 1 || docErr.Err != ErrNoItems {
+		t.Errorf("expected document 1 error, got %v", docErr)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = BuildBatchContext(ctx, []*Document{first, second})
+	docErr, ok = err.(*DocumentError)
+	if !ok || docErr.Index != 0 || docErr.Err != context.Canceled {
+		t.Errorf("expected context canceled error on document 0, got %v", err)
+	}
+}
+
+func TestBuildBatchPaymentQR(t *testing.T) {
+	var docs []*Document
+	for _, unitCost := range []string{"10", "20"} {
+		doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: unitCost, Quantity: "1"})
+		doc.Options.CurrencyCode = "EUR"
+		doc.Options.PaymentQR = &PaymentQR{IBAN: "FR1420041010050500013M02606", Name: "Test Company"}
+		docs = append(docs, doc)
+	}
+
+	pdf, err := BuildBatch(docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := &bytes.Buffer{}
+	if err := pdf.Output(output); err != nil {
+		t.Fatal(err)
+	}
+
+	if count := bytes.Count(output.Bytes(), []byte("/Subtype /Image")); count != 2 {
+		t.Errorf("expected 2 distinct qr code images, got %d", count)
+	}
+}
+
+func TestItemImageName(t *testing.T) {
+	logoBytes, _ := ioutil.ReadFile("./example_logo.png")
+	doc := newTestDocument(Invoice,
+		&Item{Name: "First", UnitCost: "10", Quantity: "1", Image: logoBytes, ImageMime: "image/png"},
+		&Item{Name: "Second", UnitCost: "10", Quantity: "1", Image: logoBytes, ImageMime: "image/png"},
+	)
+
+	pdf, err := doc.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pdf.GetImageInfo(imageName("item-image", logoBytes)) == nil {
+		t.Errorf("expected item images registered under a content derived name")
+	}
+}
+
+func TestAutoHideEmptyColumns(t *testing.T) {
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.Options.AutoHideEmptyColumns = true
+	doc.Options.ItemColumns = []ItemColumn{
+		{Name: ItemColumnName, Width: 2},
+		{Name: ItemColumnTax, Width: 1},
+		{Name: ItemColumnTotalTTC, Width: 1},
+	}
+
+	columns := doc.ColumnLayout()
+	if len(columns) != 2 || columns[0].Name != ItemColumnName || columns[0].Width != 3*columns[1].Width {
+		t.Errorf("expected tax column width given to name column, got %+v", columns)
+	}
+
+	doc.SetDefaultTax(&Tax{Percent: "20"})
+	if columns := doc.ColumnLayout(); len(columns) != 3 {
+		t.Errorf("expected tax column with default tax, got %+v", columns)
+	}
+
+	if len(doc.Options.ItemColumns) != 3 || doc.Options.ItemColumns[0].Width != 2 {
+		t.Errorf("layout modified options columns %+v", doc.Options.ItemColumns)
+	}
+}
+
+func TestLateFee(t *testing.T) {
+	dueDate := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	amount := decimal.NewFromFloat(1000)
+	percent := decimal.NewFromFloat(1.5)
+
+	if fee := ComputeLateFee(amount, percent, LateFeePeriodMonth, dueDate, dueDate.AddDate(0, 0, 60)); !fee.Equal(decimal.NewFromFloat(30)) {
+		t.Errorf("expected late fee 30, got %s", fee)
+	}
+
+	if fee := ComputeLateFee(amount, percent, LateFeePeriodMonth, dueDate, dueDate); !fee.IsZero() {
+		t.Errorf("expected no late fee before due date, got %s", fee)
+	}
+
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "1000", Quantity: "1"})
+	doc.DueDateTime = dueDate
+	doc.SetLateFee(&LateFee{Percent: "1", Period: LateFeePeriodDay, Date: dueDate.AddDate(0, 0, 3)})
+
+	totals, err := doc.ComputeTotals()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !totals.LateFee.Equal(decimal.NewFromFloat(30)) || !totals.AmountDue.Equal(decimal.NewFromFloat(1030)) {
+		t.Errorf("expected late fee 30 and amount due 1030, got %s and %s", totals.LateFee, totals.AmountDue)
+	}
+
+	doc.Options.CurrencyCode = "EUR"
+	output, err := doc.ExportUBL()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(output, []byte(`<cbc:PayableAmount currencyID="EUR">1000.00</cbc:PayableAmount>`)) {
+		t.Errorf("expected payable amount without late fee in:\n%s", output)
+	}
+
+	output, err = doc.ExportCII(FacturXProfileEN16931)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(output, []byte("<ram:DuePayableAmount>1000.00</ram:DuePayableAmount>")) {
+		t.Errorf("expected due payable amount without late fee in:\n%s", output)
+	}
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFormatRef(t *testing.T) {
+	date := time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC)
+
+	if ref := FormatRef("INV-{year}-{seq:06d}", 42, date); ref != "INV-2024-000042" {
+		t.Errorf("expected INV-2024-000042, got %s", ref)
+	}
+
+	if ref := FormatRef("{yy}{month}/{seq}-{unknown}", 1234567, date); ref != "2403/1234567-{unknown}" {
+		t.Errorf("expected 2403/1234567-{unknown}, got %s", ref)
+	}
+
+	doc := newTestDocument(Invoice)
+	doc.Options.RefFormat = "Q{year}{seq:04d}"
+	doc.DateTime = date
+	if doc.SetRefSeq(7); doc.Ref != "Q20240007" {
+		t.Errorf("expected Q20240007, got %s", doc.Ref)
+	}
+}
+
+func TestRemitTo(t *testing.T) {
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.SetPaymentTerm("30 days")
+	doc.SetRemitTo(&Contact{Name: "Lockbox 1234", Address: &Address{Address: "PO Box 1234", PostalCode: "75001", City: "Paris"}})
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	doc.SetRemitTo(&Contact{})
+	if _, err := doc.Build(); err == nil {
+		t.Errorf("expected error for remit to without name")
+	}
+}
+
+func TestNumberGrouping(t *testing.T) {
+	tests := []struct {
+		grouping string
+		out      string
+	}{
+		{NumberGroupingWestern, "$1,234,567.00"},
+		{NumberGroupingIndian, "$12,34,567.00"},
+	}
+
+	for _, test := range tests {
+		doc, _ := New(Invoice, &Options{CurrencySymbol: "$", CurrencyThousand: ",", NumberGrouping: test.grouping})
+		if out := doc.Options.moneyFormatter().FormatMoneyDecimal(decimal.NewFromFloat(1234567)); out != test.out {
+			t.Errorf("%s grouping = %q, expected %q", test.grouping, out, test.out)
+		}
+	}
+}
+
+func TestAmountInWords(t *testing.T) {
+	tests := []struct {
+		value    string
+		currency string
+		out      string
+	}{
+		{"1200.34", "", "One thousand two hundred and 34/100"},
+		{"1", "USD", "One dollar and 0 cents"},
+		{"2000015.5", "EUR", "Two million fifteen euros and 50 cents"},
+		{"1.01", "EUR", "One euro and 1 cent"},
+		{"1234.56", "INR", "One thousand two hundred thirty-four rupees and 56 paise"},
+		{"-99.999", "", "Minus one hundred and 00/100"},
+		{"0.07", "GBP", "Zero pounds and 7 pence"},
+	}
+
+	for _, test := range tests {
+		if out := AmountInWords(decimal.RequireFromString(test.value), "en", test.currency); out != test.out {
+			t.Errorf("AmountInWords(%s) = %q, expected %q", test.value, out, test.out)
+		}
+	}
+
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "1234.56", Quantity: "1"})
+	doc.Options.ShowAmountInWords = true
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	doc.Type = Proforma
+	if doc.showsAmountInWords() {
+		t.Errorf("unexpected amount in words on proforma")
+	}
+}
+
+func TestBuildContext(t *testing.T) {
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+
+	if _, err := doc.BuildContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := doc.BuildContext(ctx); err != context.Canceled {
+		t.Errorf("expected context canceled error, got %v", err)
+	}
+}
+
+func TestQuantityPrecision(t *testing.T) {
+	doc := newTestDocument(Invoice, &Item{Name: "Consulting", UnitCost: "90", Quantity: "1.333", Unit: "hrs"})
+
+	if quantity := doc.Items[0].quantityString(doc.Options); quantity != "1.333" {
+		t.Errorf("expected quantity as entered, got %s", quantity)
+	}
+
+	doc.Options.QuantityPrecision = 2
+	if quantity := doc.Items[0].quantityString(doc.Options); quantity != "1.33" {
+		t.Errorf("expected quantity 1.33, got %s", quantity)
+	}
+
+	doc.AppendItem(&Item{Name: "Support", UnitCost: "90", Quantity: "1.5"})
+	if quantity := doc.Items[1].quantityString(doc.Options); quantity != "1.50" {
+		t.Errorf("expected quantity 1.50, got %s", quantity)
+	}
+
+	totals, err := doc.ComputeTotals()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !totals.TotalNet.Equal(decimal.RequireFromString("254.97")) {
+		t.Errorf("expected total on full precision quantities 254.97, got %s", totals.TotalNet)
+	}
+}
+
+func TestFooterMessage(t *testing.T) {
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.Options.ShowPageNumbers = true
+	doc.SetPaymentTerm("30 days")
+	doc.SetFooterMessage("Thank you for your business!")
+
+	pdf, layout, err := doc.BuildWithLayout()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if layout.PageCount != 1 || layout.FinalY > doc.maxPageHeight()+5 {
+		t.Errorf("expected footer message above page number on single page, got %+v", layout)
+	}
+
+	if err := pdf.Output(&bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSwapPartyColumns(t *testing.T) {
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.Options.SwapPartyColumns = true
+	doc.Customer.Address = &Address{Address: "1 rue de la Paix", Address2: "Bat. A", PostalCode: "75001", City: "Paris", Country: "France"}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(doc.Options.MarginLeft, doc.Options.MarginTop, doc.Options.MarginRight)
+	if companyX(doc.Options, pdf) <= customerX(doc.Options, pdf) {
+		t.Errorf("expected company on the right of customer")
+	}
+
+	doc.Options.RTL = true
+	if companyX(doc.Options, pdf) >= customerX(doc.Options, pdf) {
+		t.Errorf("expected company on the left of customer when swapped and RTL")
+	}
+
+	doc.Options.RTL = false
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPaymentQRValidation(t *testing.T) {
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.Options.PaymentQR = &PaymentQR{IBAN: "FR1420041010050500013M02606", Name: "Test Company"}
+
+	fieldErr, ok := doc.Validate().(*FieldError)
+	if !ok || fieldErr.Err != ErrPaymentQRCurrency {
+		t.Errorf("expected currency error without EUR currency, got %v", doc.Validate())
+	}
+
+	doc.Options.CurrencyCode = "EUR"
+	if err := doc.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	credit := newTestDocument(CreditNote, &Item{Name: "Test", UnitCost: "-10", Quantity: "1"})
+	credit.Options.CurrencyCode = "EUR"
+	credit.Options.PaymentQR = doc.Options.PaymentQR
+
+	fieldErr, ok = credit.Validate().(*FieldError)
+	if !ok || fieldErr.Err != ErrPaymentQRAmount {
+		t.Errorf("expected amount error on credit note, got %v", credit.Validate())
+	}
+
+	for _, name := range []string{"", strings.Repeat("a", 71)} {
+		doc.Options.PaymentQR = &PaymentQR{IBAN: "FR1420041010050500013M02606", Name: name}
+		fieldErr, ok = doc.Validate().(*FieldError)
+		if !ok || fieldErr.Field != "Options.PaymentQR.Name" || fieldErr.Err != ErrPaymentQRName {
+			t.Errorf("expected name error for %q, got %v", name, doc.Validate())
+		}
+	}
+}
+
+func TestForeignCurrency(t *testing.T) {
+	doc := newTestDocument(Invoice,
+		&Item{Name: "Symbol", UnitCost: "10", Quantity: "1", Currency: "€"},
+		&Item{Name: "Code", UnitCost: "10", Quantity: "1", Currency: "eur"},
+		&Item{Name: "Foreign", UnitCost: "10", Quantity: "1", Currency: "$"},
+	)
+	doc.Options.CurrencyCode = "EUR"
+
+	if currencies := doc.foreignCurrencies(); len(currencies) != 1 || currencies[0] != "$" {
+		t.Errorf("expected only $ as foreign currency, got %v", currencies)
+	}
+
+	doc.Options.ExchangeRates = map[string]decimal.Decimal{"$": decimal.NewFromFloat(0.5)}
+	doc.Options.SummaryItemsThreshold = 1
+	if items := doc.summaryItems(); len(items) != 1 || items[0].UnitCost != "25" {
+		t.Errorf("expected summary of converted items, got %+v", items[0])
+	}
+}
+
+func TestRoundPerLine(t *testing.T) {
+	doc := newTestDocument(Invoice,
+		&Item{Name: "First", UnitCost: "0.333", Quantity: "1"},
+		&Item{Name: "Second", UnitCost: "0.333", Quantity: "1"},
+	)
+
+	totals, _ := doc.ComputeTotals()
+	if !totals.TotalNet.Equal(decimal.RequireFromString("0.666")) {
+		t.Errorf("expected unrounded total 0.666, got %s", totals.TotalNet)
+	}
+
+	doc.Options.RoundPerLine = true
+	totals, _ = doc.ComputeTotals()
+	if !totals.TotalNet.Equal(decimal.RequireFromString("0.66")) {
+		t.Errorf("expected lines rounded half up without rounding mode, total 0.66, got %s", totals.TotalNet)
+	}
+
+	doc.Options.RoundingMode = "half-down"
+	fieldErr, ok := doc.Validate().(*FieldError)
+	if !ok || fieldErr.Field != "Options.RoundingMode" || fieldErr.Err != ErrInvalidOption {
+		t.Errorf("expected invalid rounding mode error, got %v", doc.Validate())
+	}
+}
+
+func TestProformaAmountDueRows(t *testing.T) {
+	doc := newTestDocument(Proforma, &Item{Name: "Test", UnitCost: "100", Quantity: "1"})
+	doc.SetWithholding(&Tax{Percent: "10"})
+
+	if rows := doc.amountDueRows(doc.computeTotals()); len(rows) != 0 {
+		t.Errorf("expected no amount due rows on proforma, got %+v", rows)
+	}
+
+	doc.Type = Invoice
+	if rows := doc.amountDueRows(doc.computeTotals()); len(rows) != 2 {
+		t.Errorf("expected withholding and amount due rows on invoice, got %+v", rows)
+	}
+}
+
+func TestMarshalZeroDates(t *testing.T) {
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(encoded, []byte("date_time")) {
+		t.Errorf("expected zero dates left out, got %s", encoded)
+	}
+
+	doc.SetDueDateTime(time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC))
+	encoded, _ = json.Marshal(doc)
+
+	decoded, err := NewDocumentFromJSON(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !decoded.DueDateTime.Equal(doc.DueDateTime) || !decoded.DateTime.IsZero() {
+		t.Errorf("dates not decoded: %v %v", decoded.DateTime, decoded.DueDateTime)
+	}
+}
+
+func TestExportUBLPayableAmount(t *testing.T) {
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.SetWithholding(&Tax{Percent: "10"})
+
+	if _, err := doc.ExportUBL(); err == nil {
+		t.Errorf("expected error without currency code")
+	}
+
+	doc.Options.CurrencyCode = "EUR"
+	doc.Customer.TaxID = "FR40303265045"
+	doc.Customer.Address = &Address{Address: "1 rue de la Paix", City: "Paris", Country: "France", CountryCode: "fr"}
+	output, err := doc.ExportUBL()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		`<cbc:TaxInclusiveAmount currencyID="EUR">120.00</cbc:TaxInclusiveAmount>`,
+		`<cbc:PrepaidAmount currencyID="EUR">10.00</cbc:PrepaidAmount>`,
+		`<cbc:PayableAmount currencyID="EUR">110.00</cbc:PayableAmount>`,
+		"<cbc:CompanyID>FR40303265045</cbc:CompanyID>",
+		"<cbc:IdentificationCode>FR</cbc:IdentificationCode>",
+	} {
+		if !bytes.Contains(output, []byte(expected)) {
+			t.Errorf("expected %s in:\n%s", expected, output)
+		}
+	}
+
+	doc.SetDiscount(&Discount{Percent: "10"})
+	output, err = doc.ExportUBL()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := bytes.Index(output, []byte("<cac:AllowanceCharge>"))
+	end := bytes.Index(output, []byte("</cac:AllowanceCharge>"))
+	if start < 0 || end < start || !bytes.Contains(output[start:end], []byte("<cbc:Percent>20</cbc:Percent>")) {
+		t.Errorf("expected document discount tax category in:\n%s", output)
+	}
+}
+
+func TestExportCII(t *testing.T) {
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "100", Quantity: "1", Tax: &Tax{Percent: "20"}})
+	doc.Options.CurrencyCode = "EUR"
+	doc.Company.Address = &Address{Address: "1 rue de la Paix", PostalCode: "75001", City: "Paris"}
+	doc.Company.TaxID = "FR 40 303 265 045"
+	doc.SetWithholding(&Tax{Percent: "10"})
+
+	output, err := doc.ExportCII(FacturXProfileEN16931)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"<ram:GrandTotalAmount>120.00</ram:GrandTotalAmount>",
+		"<ram:TotalPrepaidAmount>10.00</ram:TotalPrepaidAmount>",
+		"<ram:DuePayableAmount>110.00</ram:DuePayableAmount>",
+		`<ram:ID schemeID="VA">FR40303265045</ram:ID>`,
+	} {
+		if !bytes.Contains(output, []byte(expected)) {
+			t.Errorf("expected %s in:\n%s", expected, output)
+		}
+	}
+
+	if bytes.Contains(output, []byte("CountryID")) {
+		t.Errorf("expected no country id without country code")
+	}
+
+	if bytes.Contains(facturXMetadata(FacturXProfileBasic), []byte("pdfaid")) {
+		t.Errorf("unexpected pdf/a identification in factur-x metadata")
+	}
+
+	pdf, err := doc.BuildWithCII(FacturXProfileBasic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pdfOutput := &bytes.Buffer{}
+	if err := pdf.Output(pdfOutput); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(pdfOutput.Bytes(), []byte(FacturXFilename)) {
+		t.Errorf("expected embedded %s", FacturXFilename)
+	}
+}
+
+func TestMissingGlyphs(t *testing.T) {
+	doc := newTestDocument(Invoice, &Item{Name: "日本 → x", UnitCost: "10", Quantity: "1"})
+	doc.SetDescription("Café № 1…")
+
+	missing := doc.MissingGlyphs()
+	if len(missing) != 2 || missing[0] != '日' || missing[1] != '本' {
+		t.Errorf("expected 日 and 本 missing, got %q", missing)
+	}
+}
+
+func TestPaymentsValidation(t *testing.T) {
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "2000", Quantity: "1"})
+	doc.AppendPayment(&Payment{Amount: "500"}).AppendPayment(&Payment{Amount: "1,000"})
+
+	fieldErr, ok := doc.Validate().(*FieldError)
+	if !ok || fieldErr.Field != "Payments[1].Amount" || fieldErr.Err != ErrInvalidNumber {
+		t.Errorf("expected invalid payment amount error, got %v", doc.Validate())
+	}
+
+	doc.Payments[1].Amount = "1000"
+	doc.Type = Proforma
+	if rows := doc.amountDueRows(doc.computeTotals()); len(rows) != 0 {
+		t.Errorf("expected no balance due rows on proforma, got %+v", rows)
+	}
+}
+
+func TestTableBorders(t *testing.T) {
+	// Titles band is 6mm high, first row starting 8mm below its top and next rows 6mm apart
+	titlesY := 50.0
+	baseY, endY := titlesY+8, titlesY+11
+	top, bottom := itemRowBorders(baseY, endY)
+	if top != titlesY+6 {
+		t.Errorf("expected first row border at titles bottom %v, got %v", titlesY+6, top)
+	}
+
+	if nextTop, _ := itemRowBorders(endY+6, endY+9); nextTop != bottom {
+		t.Errorf("expected next row border at %v, got %v", bottom, nextTop)
+	}
+
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "1"})
+	doc.Options.TableBorders = TableBordersFull
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	doc.Options.TableBorders = "ful"
+	fieldErr, ok := doc.Validate().(*FieldError)
+	if !ok || fieldErr.Field != "Options.TableBorders" || fieldErr.Err != ErrInvalidOption {
+		t.Errorf("expected invalid table borders error, got %v", doc.Validate())
+	}
+}
+
+func TestZeroBaseItems(t *testing.T) {
+	items := []*Item{
+		{Name: "Zero quantity", UnitCost: "10", Quantity: "0", Tax: &Tax{Amount: "2"}},
+		{Name: "Free", UnitCost: "10", Quantity: "1", Discount: &Discount{Percent: "100"}, Tax: &Tax{Amount: "2"}},
+		{Name: "Zero cost", UnitCost: "0", Quantity: "1", Discount: &Discount{Amount: "0"}},
+	}
+
+	for _, item := range items {
+		doc := newTestDocument(Invoice, item)
+		doc.Options.ZeroQuantityItems = ZeroQuantityIncluded
+		if _, err := doc.Build(); err != nil {
+			t.Errorf("%s: unexpected build error %v", item.Name, err)
+		}
+	}
+}
+
+func TestComputeTotalsOptions(t *testing.T) {
+	doc := &Document{Type: Invoice, Items: []*Item{{Name: "Test", UnitCost: "10", Quantity: "1"}}}
+	if _, err := doc.ComputeTotals(); err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.Options != nil {
+		t.Errorf("compute totals set document options")
+	}
+
+	doc.Options = &Options{CurrencyCode: "EUR"}
+	if _, err := doc.ComputeTotals(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.Options.CurrencySymbol) > 0 {
+		t.Errorf("compute totals set options defaults: %+v", doc.Options)
+	}
+}
+
+func TestShowDiscountAmount(t *testing.T) {
+	doc := newTestDocument(Invoice, &Item{Name: "Test", UnitCost: "10", Quantity: "2", Discount: &Discount{Percent: "10"}})
+	percentItem := doc.Items[0]
+	amountItem := &Item{Name: "Test", UnitCost: "10", Quantity: "2", Discount: &Discount{Amount: "5"}}
+
+	ac := doc.Options.moneyFormatter()
+	if title, desc := percentItem.discountLines(doc.Options, ac, "€"); title != "10 %" || len(desc) > 0 {
+		t.Errorf("expected percent without saved amount, got %q, %q", title, desc)
+	}
+
+	if _, desc := amountItem.discountLines(doc.Options, ac, "€"); desc != "-25.00 %" {
+		t.Errorf("expected amount discount percent equivalent, got %q", desc)
+	}
+
+	doc.Options.ShowDiscountAmount = true
+	if _, desc := percentItem.discountLines(doc.Options, ac, "€"); desc != "-"+ac.FormatMoneyDecimal(decimal.NewFromFloat(2)) {
+		t.Errorf("expected saved amount, got %q", desc)
+	}
+
+	if _, desc := amountItem.discountLines(doc.Options, ac, "€"); desc != "-25.00 %" {
+		t.Errorf("expected amount discount percent equivalent with saved amount option, got %q", desc)
+	}
+
+	if _, err := doc.Build(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,60 @@
+package generator
+
+// MissingGlyphs return characters of document texts which can't be rendered
+// with the document font, nor approximated, and would be replaced by "?" in
+// output. Custom UTF-8 fonts are not checked.
+func (d *Document) MissingGlyphs() []rune {
+	if d.Options != nil && d.Options.Font != nil {
+		return nil
+	}
+
+	var missing []rune
+	seen := map[rune]bool{}
+
+	for _, str := range d.texts() {
+		for _, r := range str {
+			if !encodable(r) && !seen[r] {
+				seen[r] = true
+				missing = append(missing, r)
+			}
+		}
+	}
+
+	return missing
+}
+
+// texts return all document texts rendered in pdf
+func (d *Document) texts() []string {
+	texts := []string{d.Ref, d.Version, d.Description, d.Notes, d.PaymentTerm}
+
+	if d.Header != nil {
+		texts = append(texts, d.Header.Text, d.Header.Title)
+		texts = append(texts, d.Header.Lines...)
+	}
+
+	if d.Footer != nil {
+		texts = append(texts, d.Footer.Text)
+	}
+
+	for _, contact := range []*Contact{d.Company, d.Customer} {
+		if contact == nil {
+			continue
+		}
+
+		texts = append(texts, contact.Name)
+
+		if contact.Address != nil {
+			texts = append(texts, contact.Address.ToString())
+		}
+	}
+
+	for _, item := range d.Items {
+		texts = append(texts, item.Name, item.Description)
+	}
+
+	if d.Discount != nil {
+		texts = append(texts, d.Discount.Label)
+	}
+
+	return texts
+}
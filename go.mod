@@ -7,12 +7,13 @@ require (
 	github.com/creasty/defaults v1.3.0
 	github.com/go-playground/locales v0.12.1 // indirect
 	github.com/go-playground/universal-translator v0.16.0 // indirect
-	github.com/jung-kurt/gofpdf v1.1.0
+	github.com/jung-kurt/gofpdf v1.16.2
 	github.com/leekchan/accounting v0.0.0-20180703100437-18a1925d6514
 	github.com/leodido/go-urn v1.1.0 // indirect
 	github.com/lib/pq v1.8.0 // indirect
 	github.com/pkg/errors v0.8.1 // indirect
 	github.com/shopspring/decimal v0.0.0-20180709203117-cd690d0c9e24
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
 	github.com/stretchr/testify v1.6.1 // indirect
 	gopkg.in/go-playground/assert.v1 v1.2.1 // indirect
 	gopkg.in/go-playground/validator.v9 v9.28.0
@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/shopspring/decimal"
+)
+
+// itemsGroup define items sharing the same Item.Group
+type itemsGroup struct {
+	name  string
+	items []*Item
+}
+
+// groupItems split items by group in order of first appearance, ungrouped
+// items being last
+func groupItems(items []*Item) []itemsGroup {
+	var groups []itemsGroup
+	indexes := map[string]int{}
+	var ungrouped []*Item
+
+	for _, item := range items {
+		if len(item.Group) == 0 {
+			ungrouped = append(ungrouped, item)
+			continue
+		}
+
+		index, ok := indexes[item.Group]
+		if !ok {
+			index = len(groups)
+			indexes[item.Group] = index
+			groups = append(groups, itemsGroup{name: item.Group})
+		}
+
+		groups[index].items = append(groups[index].items, item)
+	}
+
+	if len(ungrouped) > 0 {
+		groups = append(groups, itemsGroup{items: ungrouped})
+	}
+
+	return groups
+}
+
+// appendGroupedItems draw items under their group heading, each group being
+// followed by its subtotal
+func (d *Document) appendGroupedItems(ctx context.Context, items []*Item, columns []ColumnBounds, pdf *gofpdf.Fpdf) error {
+	cols := columnsByName(columns)
+	index := 0
+
+	for _, group := range groupItems(items) {
+		// Keep heading with group first row
+		name := group.name
+		if len(name) == 0 {
+			name = d.Options.TextItemsUngrouped
+		}
+
+		if index > 0 && pdf.GetY()+8+group.items[0].height(d.Options, cols, pdf) > d.maxPageHeight() {
+			d.appendItemsPage(pdf)
+		}
+
+		pdf.SetFont(d.Options.fontFamily(), "B", 8)
+		pdf.SetX(d.Options.MarginLeft)
+		pdf.CellFormat(d.contentWidth(), 4, d.Options.encodeString(name), "0", 0, d.Options.align("L"), false, 0, "")
+		pdf.SetFont(d.Options.fontFamily(), "", 8)
+		pdf.SetXY(d.Options.MarginLeft, pdf.GetY()+8)
+
+		for _, item := range group.items {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			d.appendItemRow(item, index, columns, pdf)
+			index++
+		}
+
+		d.appendGroupSubtotal(group.items, pdf)
+	}
+
+	return nil
+}
+
+// appendGroupSubtotal draw the sum of group items totals without tax, discounts included
+func (d *Document) appendGroupSubtotal(items []*Item, pdf *gofpdf.Fpdf) {
+	subtotal := decimal.NewFromFloat(0)
+	for _, item := range d.convertedItems(items) {
+		subtotal = subtotal.Add(item.totalWithoutTaxAndWithDiscount())
+	}
+
+	if pdf.GetY()+4 > d.maxPageHeight() {
+		d.appendItemsPage(pdf)
+	}
+
+	subtotalString := fmt.Sprintf(
+		"%s: %s",
+		d.Options.encodeString(d.Options.TextItemsSubtotal),
+		d.Options.moneyFormatter().FormatMoneyDecimal(subtotal),
+	)
+
+	pdf.SetFont(d.Options.fontFamily(), "B", 8)
+	pdf.SetX(d.Options.MarginLeft)
+	pdf.CellFormat(d.contentWidth(), 4, subtotalString, "0", 0, d.Options.align("R"), false, 0, "")
+	pdf.SetFont(d.Options.fontFamily(), "", 8)
+	pdf.SetXY(d.Options.MarginLeft, pdf.GetY()+10)
+}
@@ -7,12 +7,19 @@ import (
 	"github.com/jung-kurt/gofpdf"
 )
 
-// HeaderFooter define header or footer informations on document
+// HeaderFooter define header or footer informations on document.
+// Logo, Title, Lines and Divider are only drawn in header, Text being drawn
+// when none of Logo, Title and Lines is set.
 type HeaderFooter struct {
-	UseCustomFunc bool    `json:"-"`
-	Text          string  `json:"text,omitempty"`
-	FontSize      float64 `json:"font_size,omitempty" default:"7"`
-	Pagination    bool    `json:"pagination,omitempty"`
+	UseCustomFunc bool     `json:"-"`
+	Text          string   `json:"text,omitempty"`
+	FontSize      float64  `json:"font_size,omitempty" default:"7"`
+	Pagination    bool     `json:"pagination,omitempty"`
+	Logo          *Logo    `json:"logo,omitempty"`                         // Logo drawn on the left
+	Title         string   `json:"title,omitempty"`                        // Centered title, ex company name
+	TitleFontSize float64  `json:"title_font_size,omitempty" default:"12"` // Title font size
+	Lines         []string `json:"lines,omitempty"`                        // Contact lines drawn on the right
+	Divider       bool     `json:"divider,omitempty"`                      // Draw a line below header
 }
 
 type fnc func()
@@ -22,8 +29,19 @@ func (hf *HeaderFooter) ApplyFunc(pdf *gofpdf.Fpdf, fn fnc) {
 	pdf.SetHeaderFunc(fn)
 }
 
+// withDefaults return a copy of hf with defaults set, hf being left untouched
+func (hf *HeaderFooter) withDefaults() (*HeaderFooter, error) {
+	header := *hf
+	if err := defaults.Set(&header); err != nil {
+		return nil, err
+	}
+
+	return &header, nil
+}
+
 func (hf *HeaderFooter) applyHeader(d *Document, pdf *gofpdf.Fpdf) error {
-	if err := defaults.Set(hf); err != nil {
+	hf, err := hf.withDefaults()
+	if err != nil {
 		return err
 	}
 
@@ -31,38 +49,142 @@ func (hf *HeaderFooter) applyHeader(d *Document, pdf *gofpdf.Fpdf) error {
 		pdf.SetHeaderFunc(func() {
 			currentY := pdf.GetY()
 			currentX := pdf.GetX()
+			pageWidth, _ := d.pageSize()
+
+			// Draw watermark first so it stays behind content
+			d.appendWatermark(pdf)
 
 			pdf.SetTopMargin(HeaderMarginTop)
 			pdf.SetY(HeaderMarginTop)
 
-			pdf.SetLeftMargin(BaseMargin)
-			pdf.SetRightMargin(BaseMargin)
-
-			// Parse Text as html (simple)
-			pdf.SetFont("Helvetica", "", hf.FontSize)
-			_, lineHt := pdf.GetFontSize()
-			html := pdf.HTMLBasicNew()
-			html.Write(lineHt, hf.Text)
+			pdf.SetLeftMargin(d.Options.MarginLeft)
+			pdf.SetRightMargin(d.Options.MarginRight)
+
+			if hf.hasLayout() {
+				hf.appendLayout(d, pdf)
+			} else {
+				// Parse Text as html (simple)
+				pdf.SetFont(d.Options.fontFamily(), "", hf.FontSize)
+				_, lineHt := pdf.GetFontSize()
+				html := pdf.HTMLBasicNew()
+				html.Write(lineHt, hf.Text)
+			}
 
 			// Apply pagination
 			if !hf.Pagination {
 				pdf.AliasNbPages("") // Will replace {nb} with total page count
 				pdf.SetY(HeaderMarginTop + 8)
-				pdf.SetX(195)
-				pdf.CellFormat(10, 5, fmt.Sprintf("Page %d/{nb}", pdf.PageNo()), "0", 0, "R", false, 0, "")
+				pdf.SetX(d.mirrorX(pageWidth-d.Options.MarginRight-5, 10))
+				pdf.CellFormat(10, 5, fmt.Sprintf("Page %d/{nb}", pdf.PageNo()), "0", 0, d.Options.align("R"), false, 0, "")
 			}
 
 			pdf.SetY(currentY)
 			pdf.SetX(currentX)
-			pdf.SetMargins(BaseMargin, BaseMarginTop, BaseMargin)
+			pdf.SetMargins(d.Options.MarginLeft, d.marginTop(), d.Options.MarginRight)
+
+			// Repeat company logo
+			d.appendRepeatedLogo(pdf)
 		})
 	}
 
 	return nil
 }
 
+// hasLayout return true when header logo, title or lines are set
+func (hf *HeaderFooter) hasLayout() bool {
+	return hf.Logo != nil || len(hf.Title) > 0 || len(hf.Lines) > 0
+}
+
+// headerLineHeight return height in mm of a header line of font size in points
+func headerLineHeight(fontSize float64) float64 {
+	return fontSize * 25.4 / 72 * 1.2
+}
+
+// height return header layout height in mm below HeaderMarginTop, divider
+// included, or 0 when header has no layout
+func (hf *HeaderFooter) height() float64 {
+	if !hf.hasLayout() {
+		return 0
+	}
+
+	hf, err := hf.withDefaults()
+	if err != nil {
+		return 0
+	}
+
+	height := float64(len(hf.Lines)) * headerLineHeight(hf.FontSize)
+	if titleHeight := headerLineHeight(hf.TitleFontSize); len(hf.Title) > 0 && titleHeight > height {
+		height = titleHeight
+	}
+
+	if hf.Logo != nil {
+		if _, logoHeight, ok := hf.Logo.size(); ok && logoHeight > height {
+			height = logoHeight
+		}
+	}
+
+	if hf.Divider {
+		height += 2
+	}
+
+	return height
+}
+
+// appendLayout draw header logo on the left, title centered and lines on the
+// right, sides being swapped when RTL, then the divider line
+func (hf *HeaderFooter) appendLayout(d *Document, pdf *gofpdf.Fpdf) {
+	height := hf.height()
+	width := d.contentWidth()
+
+	if hf.Logo != nil {
+		if logoWidth, _, ok := hf.Logo.size(); ok {
+			hf.Logo.appendTo("header-logo", d.mirrorX(d.Options.MarginLeft, logoWidth), HeaderMarginTop, pdf)
+		}
+	}
+
+	if len(hf.Title) > 0 {
+		pdf.SetFont(d.Options.fontFamily(), "B", hf.TitleFontSize)
+		pdf.SetXY(d.Options.MarginLeft, HeaderMarginTop)
+		pdf.CellFormat(width, headerLineHeight(hf.TitleFontSize), d.Options.encodeString(hf.Title), "0", 0, "C", false, 0, "")
+	}
+
+	lineHeight := headerLineHeight(hf.FontSize)
+	pdf.SetFont(d.Options.fontFamily(), "", hf.FontSize)
+	pdf.SetTextColor(d.Options.Theme.GreyTextColor[0], d.Options.Theme.GreyTextColor[1], d.Options.Theme.GreyTextColor[2])
+	for i, line := range hf.Lines {
+		pdf.SetXY(d.Options.MarginLeft, HeaderMarginTop+float64(i)*lineHeight)
+		pdf.CellFormat(width, lineHeight, d.Options.encodeString(line), "0", 0, d.Options.align("R"), false, 0, "")
+	}
+	pdf.SetTextColor(d.Options.Theme.BaseTextColor[0], d.Options.Theme.BaseTextColor[1], d.Options.Theme.BaseTextColor[2])
+
+	if hf.Divider {
+		y := HeaderMarginTop + height - 1
+		pdf.SetDrawColor(d.Options.Theme.DarkBgColor[0], d.Options.Theme.DarkBgColor[1], d.Options.Theme.DarkBgColor[2])
+		pdf.Line(d.Options.MarginLeft, y, d.Options.MarginLeft+width, y)
+		pdf.SetDrawColor(0, 0, 0)
+	}
+}
+
+// appendRepeatedLogo draw company logo on pages after the first one if
+// LogoOnEveryPage is set, and move body start below it
+func (d *Document) appendRepeatedLogo(pdf *gofpdf.Fpdf) {
+	if !d.Options.LogoOnEveryPage || pdf.PageNo() <= 1 || d.Company == nil || d.Company.Logo == nil {
+		return
+	}
+
+	currentY := pdf.GetY()
+	d.Company.appendLogo(d.Options.MarginLeft, d.marginTop(), pdf)
+
+	if pdf.GetY()+5 > currentY {
+		pdf.SetY(pdf.GetY() + 5)
+	} else {
+		pdf.SetY(currentY)
+	}
+}
+
 func (hf *HeaderFooter) applyFooter(d *Document, pdf *gofpdf.Fpdf) error {
-	if err := defaults.Set(hf); err != nil {
+	hf, err := hf.withDefaults()
+	if err != nil {
 		return err
 	}
 
@@ -70,12 +192,13 @@ func (hf *HeaderFooter) applyFooter(d *Document, pdf *gofpdf.Fpdf) error {
 		pdf.SetFooterFunc(func() {
 			currentY := pdf.GetY()
 			currentX := pdf.GetX()
+			pageWidth, pageHeight := d.pageSize()
 
 			pdf.SetTopMargin(HeaderMarginTop)
-			pdf.SetY(287 - HeaderMarginTop)
+			pdf.SetY(pageHeight - 10 - HeaderMarginTop)
 
 			// Parse Text as html (simple)
-			pdf.SetFont("Helvetica", "", hf.FontSize)
+			pdf.SetFont(d.Options.fontFamily(), "", hf.FontSize)
 			_, lineHt := pdf.GetFontSize()
 			html := pdf.HTMLBasicNew()
 			html.Write(lineHt, hf.Text)
@@ -83,16 +206,47 @@ func (hf *HeaderFooter) applyFooter(d *Document, pdf *gofpdf.Fpdf) error {
 			// Apply pagination
 			if hf.Pagination {
 				pdf.AliasNbPages("") // Will replace {nb} with total page count
-				pdf.SetY(287 - HeaderMarginTop - 8)
-				pdf.SetX(195)
-				pdf.CellFormat(10, 5, fmt.Sprintf("Page %d/{nb}", pdf.PageNo()), "0", 0, "R", false, 0, "")
+				pdf.SetY(pageHeight - 10 - HeaderMarginTop - 8)
+				pdf.SetX(d.mirrorX(pageWidth-d.Options.MarginRight-5, 10))
+				pdf.CellFormat(10, 5, fmt.Sprintf("Page %d/{nb}", pdf.PageNo()), "0", 0, d.Options.align("R"), false, 0, "")
 			}
 
 			pdf.SetY(currentY)
 			pdf.SetX(currentX)
-			pdf.SetMargins(BaseMargin, BaseMarginTop, BaseMargin)
+			pdf.SetMargins(d.Options.MarginLeft, d.marginTop(), d.Options.MarginRight)
+
+			// Page numbers
+			d.appendPageNumber(pdf)
 		})
 	}
 
 	return nil
 }
+
+// appendPageNumber draw "Page X of Y" centered at page bottom if ShowPageNumbers is set
+func (d *Document) appendPageNumber(pdf *gofpdf.Fpdf) {
+	if !d.Options.ShowPageNumbers {
+		return
+	}
+
+	currentY := pdf.GetY()
+	currentX := pdf.GetX()
+	_, pageHeight := d.pageSize()
+
+	pdf.AliasNbPages("") // Will replace {nb} with total page count
+	pdf.SetFont(d.Options.fontFamily(), "", SmallTextFontSize)
+	pdf.SetXY(d.Options.MarginLeft, pageHeight-10)
+	pdf.CellFormat(
+		d.contentWidth(),
+		5,
+		fmt.Sprintf("%s %d %s {nb}", d.Options.encodeString(d.Options.TextPageTitle), pdf.PageNo(), d.Options.encodeString(d.Options.TextPageOfTitle)),
+		"0",
+		0,
+		"C",
+		false,
+		0,
+		"",
+	)
+
+	pdf.SetXY(currentX, currentY)
+}
@@ -2,20 +2,48 @@ package generator
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/jung-kurt/gofpdf"
-	"github.com/leekchan/accounting"
 	"github.com/shopspring/decimal"
 )
 
 // Item represent a 'product' or a 'service'
 type Item struct {
 	Name        string    `json:"name,omitempty" validate:"required"`
-	Description string    `json:"description,omitempty"`
+	Description string    `json:"description,omitempty"` // Smaller grey line under name, row height grows to fit
 	UnitCost    string    `json:"unit_cost,omitempty"`
 	Quantity    string    `json:"quantity,omitempty"`
-	Tax         *Tax      `json:"tax,omitempty"`
+	Unit        string    `json:"unit,omitempty"`  // Unit of measure printed after quantity, ex hrs, pcs, kg
+	Tax         *Tax      `json:"tax,omitempty"`   // Single tax, shortcut for Taxes
+	Taxes       []*Tax    `json:"taxes,omitempty"` // Taxes applied in order, replace Tax when set
 	Discount    *Discount `json:"discount,omitempty"`
+	Group       string    `json:"group,omitempty"`      // Items section heading, see Options.GroupItems
+	Taxable     *bool     `json:"taxable,omitempty"`    // Default to true, non taxable items ignore taxes
+	Currency    string    `json:"currency,omitempty"`   // Currency symbol of item amounts, default to document currency symbol
+	Image       []byte    `json:"image,omitempty"`      // Thumbnail rendered in a leading column
+	ImageMime   string    `json:"image_mime,omitempty"` // image/png or image/jpeg
+}
+
+func (i *Item) taxable() bool {
+	return i.Taxable == nil || *i.Taxable
+}
+
+// taxes return item taxes in order, none if item is not taxable
+func (i *Item) taxes() []*Tax {
+	if !i.taxable() {
+		return nil
+	}
+
+	if len(i.Taxes) > 0 {
+		return i.Taxes
+	}
+
+	if i.Tax != nil {
+		return []*Tax{i.Tax}
+	}
+
+	return nil
 }
 
 func (i *Item) unitCost() decimal.Decimal {
@@ -28,6 +56,16 @@ func (i *Item) quantity() decimal.Decimal {
 	return quantity
 }
 
+// quantityString return quantity as printed, rounded to Options.QuantityPrecision
+// decimals when set, totals being computed on full precision quantity
+func (i *Item) quantityString(options *Options) string {
+	if options.QuantityPrecision > 0 {
+		return i.quantity().StringFixed(int32(options.QuantityPrecision))
+	}
+
+	return i.quantity().String()
+}
+
 func (i *Item) totalWithoutTax() decimal.Decimal {
 	quantity, _ := decimal.NewFromString(i.Quantity)
 	price, _ := decimal.NewFromString(i.UnitCost)
@@ -55,279 +93,414 @@ func (i *Item) totalWithoutTaxAndWithDiscount() decimal.Decimal {
 	return total
 }
 
-func (i *Item) totalWithTaxAndDiscount() decimal.Decimal {
-	return i.totalWithoutTaxAndWithDiscount().Add(i.taxWithDiscount())
+// discountExceedsTotal return true if item discount is greater than quantity x unit cost,
+// percents above 100 being accepted when clamped
+func (i *Item) discountExceedsTotal(clamp bool) bool {
+	if i.Discount == nil {
+		return false
+	}
+
+	dType, dNum, _ := i.Discount.parse()
+	if dType == "amount" {
+		return dNum.GreaterThan(i.totalWithoutTax())
+	}
+
+	return !clamp && dNum.GreaterThan(decimal.NewFromFloat(100))
+}
+
+// netWithDiscount return item total without tax, extracted from price when
+// prices include tax
+func (i *Item) netWithDiscount(included bool) decimal.Decimal {
+	if included {
+		return netFromGross(i.taxes(), i.totalWithoutTaxAndWithDiscount())
+	}
+
+	return i.totalWithoutTaxAndWithDiscount()
+}
+
+func (i *Item) totalWithTaxAndDiscount(included bool) decimal.Decimal {
+	return i.netWithDiscount(included).Add(i.taxWithDiscount(included))
+}
+
+// taxWithDiscount return item taxes, extracted from price when prices include tax
+func (i *Item) taxWithDiscount(included bool) decimal.Decimal {
+	if included {
+		return i.totalWithoutTaxAndWithDiscount().Sub(i.netWithDiscount(included))
+	}
+
+	return totalTaxes(i.taxes(), i.totalWithoutTaxAndWithDiscount())
+}
+
+// image return item thumbnail as a logo fitting in size x size, nil when item has no image
+func (i *Item) image(size float64) *Logo {
+	if len(i.Image) == 0 {
+		return nil
+	}
+
+	return &Logo{Bytes: i.Image, MimeType: i.ImageMime, MaxWidth: size, MaxHeight: size}
 }
 
-func (i *Item) taxWithDiscount() decimal.Decimal {
-	result := decimal.NewFromFloat(0)
+// height return item row height once rendered, from name and description lines
+// and image size
+func (i *Item) height(options *Options, cols map[string]ColumnBounds, pdf *gofpdf.Fpdf) float64 {
+	if options.ItemsCellPadding > 0 {
+		defer pdf.SetCellMargin(pdf.GetCellMargin())
+		pdf.SetCellMargin(options.ItemsCellPadding)
+	}
+
+	width := cols[ItemColumnName].Width
+	height := 3 * float64(linesCount(pdf.SplitLines([]byte(options.encodeString(i.Name)), width)))
+
+	if len(i.Description) > 0 {
+		pdf.SetFont(options.fontFamily(), "", SmallTextFontSize)
+		height += 1 + 3*float64(linesCount(pdf.SplitLines([]byte(options.encodeString(i.Description)), width)))
+		pdf.SetFont(options.fontFamily(), "", BaseTextFontSize)
+	}
+
+	if i.image(options.ItemImageSize) != nil && height < options.ItemImageSize {
+		height = options.ItemImageSize
+	}
+
+	return height
+}
 
-	if i.Tax == nil {
-		return result
+// linesCount return count of split lines, an empty text still taking one line
+func linesCount(lines [][]byte) int {
+	if len(lines) == 0 {
+		return 1
 	}
 
-	totalHT := i.totalWithoutTaxAndWithDiscount()
-	taxType, taxAmount := i.Tax.getTax()
+	return len(lines)
+}
 
-	if taxType == "amount" {
-		result = taxAmount
-	} else {
-		divider := decimal.NewFromFloat(100)
-		result = totalHT.Mul(taxAmount.Div(divider))
+// discountLines return discount column title, the discount as entered, and
+// description: percent equivalent of amount discounts, or saved amount of
+// percent discounts when Options.ShowDiscountAmount is set
+func (i *Item) discountLines(options *Options, ac *moneyFormatter, currencySymbol string) (string, string) {
+	discountType, discountAmount := i.Discount.getDiscount()
+	if discountType == "percent" {
+		title := fmt.Sprintf("%s %s", discountAmount, options.encodeString("%"))
+		if !options.ShowDiscountAmount {
+			return title, ""
+		}
+
+		// get saved amount
+		dAmount := i.totalWithoutTax().Sub(i.totalWithoutTaxAndWithDiscount())
+		return title, fmt.Sprintf("-%s", ac.FormatMoneyDecimal(dAmount))
 	}
 
-	return result
+	title := fmt.Sprintf("%s %s", discountAmount, options.encodeString(currencySymbol))
+	dCost := i.totalWithoutTax()
+	if dCost.IsZero() {
+		// no percent of a zero cost
+		return title, "--"
+	}
+
+	// get percent from amount
+	dPerc := discountAmount.Mul(decimal.NewFromFloat(100)).Div(dCost)
+	return title, fmt.Sprintf("-%s %%", dPerc.StringFixed(2))
 }
 
-func (i *Item) appendColTo(options *Options, pdf *gofpdf.Fpdf) {
-	ac := accounting.Accounting{
-		Symbol:    encodeString(options.CurrencySymbol),
-		Precision: options.CurrencyPrecision,
-		Thousand:  options.CurrencyThousand,
-		Decimal:   options.CurrencyDecimal,
+// appendColTo draw item row, columns being drawn in layout order
+func (i *Item) appendColTo(options *Options, columns []ColumnBounds, pdf *gofpdf.Fpdf) {
+	cols := columnsByName(columns)
+	ac := options.moneyFormatter()
+	currencySymbol := strings.TrimSpace(options.CurrencySymbol)
+	if i.foreignCurrency(options) {
+		ac.Symbol = options.encodeString(options.moneySymbol(i.Currency))
+		currencySymbol = i.Currency
+	}
+
+	// Apply cell padding
+	if options.ItemsCellPadding > 0 {
+		defer pdf.SetCellMargin(pdf.GetCellMargin())
+		pdf.SetCellMargin(options.ItemsCellPadding)
 	}
 
 	// Get base Y (top of line)
 	baseY := pdf.GetY()
 
 	// Name
-	pdf.SetX(ItemColNameOffset)
+	pdf.SetX(cols[ItemColumnName].X)
 	pdf.MultiCell(
-		ItemColUnitPriceOffset-ItemColNameOffset,
+		cols[ItemColumnName].Width,
 		3,
-		encodeString(i.Name),
-		"",
+		options.encodeString(i.Name),
 		"",
+		options.align(cols[ItemColumnName].Align),
 		false,
 	)
 
 	// Description
 	if len(i.Description) > 0 {
-		pdf.SetX(ItemColNameOffset)
+		pdf.SetX(cols[ItemColumnName].X)
 		pdf.SetY(pdf.GetY() + 1)
 
-		pdf.SetFont("Helvetica", "", SmallTextFontSize)
-		pdf.SetTextColor(GreyTextColor[0], GreyTextColor[1], GreyTextColor[2])
+		pdf.SetFont(options.fontFamily(), "", SmallTextFontSize)
+		pdf.SetTextColor(options.Theme.GreyTextColor[0], options.Theme.GreyTextColor[1], options.Theme.GreyTextColor[2])
 
 		pdf.MultiCell(
-			ItemColUnitPriceOffset-ItemColNameOffset,
+			cols[ItemColumnName].Width,
 			3,
-			encodeString(i.Description),
-			"",
+			options.encodeString(i.Description),
 			"",
+			options.align(cols[ItemColumnName].Align),
 			false,
 		)
 
 		// Reset font
-		pdf.SetFont("Helvetica", "", BaseTextFontSize)
-		pdf.SetTextColor(BaseTextColor[0], BaseTextColor[1], BaseTextColor[2])
+		pdf.SetFont(options.fontFamily(), "", BaseTextFontSize)
+		pdf.SetTextColor(options.Theme.BaseTextColor[0], options.Theme.BaseTextColor[1], options.Theme.BaseTextColor[2])
 	}
 
-	// Compute line height
+	// Compute line height, growing to fit image
 	colHeight := pdf.GetY() - baseY
-
-	// Unit price
-	pdf.SetY(baseY)
-	pdf.SetX(ItemColUnitPriceOffset)
-	pdf.CellFormat(
-		ItemColQuantityOffset-ItemColUnitPriceOffset,
-		colHeight,
-		ac.FormatMoneyDecimal(i.unitCost()),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Quantity
-	pdf.SetX(ItemColQuantityOffset)
-	pdf.CellFormat(
-		ItemColTaxOffset-ItemColQuantityOffset,
-		colHeight,
-		i.quantity().String(),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Total HT
-	pdf.SetX(ItemColTotalHTOffset)
-	pdf.CellFormat(
-		ItemColTaxOffset-ItemColTotalHTOffset,
-		colHeight,
-		ac.FormatMoneyDecimal(i.totalWithoutTax()),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
-	// Discount
-	pdf.SetX(ItemColDiscountOffset)
-	if i.Discount == nil {
-		pdf.CellFormat(
-			ItemColTotalTTCOffset-ItemColDiscountOffset,
-			colHeight,
-			"--",
-			"0",
-			0,
-			"",
-			false,
-			0,
-			"",
-		)
-	} else {
-		// If discount
-		discountType, discountAmount := i.Discount.getDiscount()
-		var discountTitle string
-		var discountDesc string
-
-		if discountType == "percent" {
-			discountTitle = fmt.Sprintf("%s %s", discountAmount, encodeString("%"))
-			// get amount from percent
-			dCost := i.totalWithoutTax()
-			dAmount := dCost.Mul(discountAmount.Div(decimal.NewFromFloat(100)))
-			discountDesc = fmt.Sprintf("-%s", ac.FormatMoneyDecimal(dAmount))
-		} else {
-			discountTitle = fmt.Sprintf("%s %s", discountAmount, encodeString("€"))
-			dCost := i.totalWithoutTax()
-			dPerc := discountAmount.Mul(decimal.NewFromFloat(100))
-			dPerc = dPerc.Div(dCost)
-			// get percent from amount
-			discountDesc = fmt.Sprintf("-%s %%", dPerc.StringFixed(2))
-		}
-
-		// discount title
-		// lastY := pdf.GetY()
-		pdf.CellFormat(
-			ItemColTotalTTCOffset-ItemColDiscountOffset,
-			colHeight/2,
-			discountTitle,
-			"0",
-			0,
-			"LB",
-			false,
-			0,
-			"",
-		)
-
-		// discount desc
-		pdf.SetXY(ItemColDiscountOffset, baseY+(colHeight/2))
-		pdf.SetFont("Helvetica", "", SmallTextFontSize)
-		pdf.SetTextColor(GreyTextColor[0], GreyTextColor[1], GreyTextColor[2])
-
-		pdf.CellFormat(
-			ItemColTotalTTCOffset-ItemColDiscountOffset,
-			colHeight/2,
-			discountDesc,
-			"0",
-			0,
-			"LT",
-			false,
-			0,
-			"",
-		)
-
-		// reset font and y
-		pdf.SetFont("Helvetica", "", BaseTextFontSize)
-		pdf.SetTextColor(BaseTextColor[0], BaseTextColor[1], BaseTextColor[2])
-		pdf.SetY(baseY)
+	if i.image(options.ItemImageSize) != nil && colHeight < options.ItemImageSize {
+		colHeight = options.ItemImageSize
 	}
 
-	// Tax
-	pdf.SetX(ItemColTaxOffset)
-	if i.Tax == nil {
-		// If no tax
-		pdf.CellFormat(
-			ItemColDiscountOffset-ItemColTaxOffset,
-			colHeight,
-			"--",
-			"0",
-			0,
-			"",
-			false,
-			0,
-			"",
-		)
-	} else {
-		// If tax
-		taxType, taxAmount := i.Tax.getTax()
-		var taxTitle string
-		var taxDesc string
-
-		if taxType == "percent" {
-			taxTitle = fmt.Sprintf("%s %s", taxAmount, encodeString("%"))
-			// get amount from percent
-			dCost := i.totalWithoutTaxAndWithDiscount()
-			dAmount := dCost.Mul(taxAmount.Div(decimal.NewFromFloat(100)))
-			taxDesc = ac.FormatMoneyDecimal(dAmount)
-		} else {
-			taxTitle = fmt.Sprintf("%s %s", taxAmount, encodeString("€"))
-			dCost := i.totalWithoutTaxAndWithDiscount()
-			dPerc := taxAmount.Mul(decimal.NewFromFloat(100))
-			dPerc = dPerc.Div(dCost)
-			// get percent from amount
-			taxDesc = fmt.Sprintf("%s %%", dPerc.StringFixed(2))
+	// Other columns
+	pdf.SetY(baseY)
+	for _, col := range columns {
+		switch col.Name {
+		case ItemColumnImage:
+			// Image
+			if image := i.image(options.ItemImageSize); image != nil {
+				image.appendTo("item-image", col.X+1, baseY, pdf)
+				pdf.SetY(baseY)
+			}
+
+		case ItemColumnUnitPrice:
+			// Unit price
+			pdf.SetX(col.X)
+			pdf.CellFormat(
+				col.Width,
+				colHeight,
+				ac.FormatMoneyDecimal(i.unitCost()),
+				"0",
+				0,
+				options.align(col.Align),
+				false,
+				0,
+				"",
+			)
+
+		case ItemColumnQuantity:
+			// Quantity (or included label)
+			quantity := i.quantityString(options)
+			if i.quantity().IsZero() && options.ZeroQuantityItems == ZeroQuantityIncluded {
+				quantity = options.encodeString(options.TextItemsIncluded)
+			} else if len(i.Unit) > 0 {
+				quantity = fmt.Sprintf("%s %s", quantity, options.encodeString(i.Unit))
+			}
+
+			pdf.SetX(col.X)
+			pdf.CellFormat(
+				col.Width,
+				colHeight,
+				quantity,
+				"0",
+				0,
+				options.align(col.Align),
+				false,
+				0,
+				"",
+			)
+
+		case ItemColumnTotalHT:
+			// Total HT, extracted from gross price when prices include tax
+			totalHT := i.totalWithoutTax()
+			if options.PricesIncludeTax {
+				totalHT = netFromGross(i.taxes(), totalHT)
+			}
+
+			pdf.SetX(col.X)
+			pdf.CellFormat(
+				col.Width,
+				colHeight,
+				ac.FormatMoneyDecimal(totalHT),
+				"0",
+				0,
+				options.align(col.Align),
+				false,
+				0,
+				"",
+			)
+
+		case ItemColumnDiscount:
+			// Discount
+			pdf.SetX(col.X)
+			if i.Discount == nil {
+				pdf.CellFormat(
+					col.Width,
+					colHeight,
+					"--",
+					"0",
+					0,
+					options.align(col.Align),
+					false,
+					0,
+					"",
+				)
+			} else {
+				// If discount
+				discountTitle, discountDesc := i.discountLines(options, ac, currencySymbol)
+
+				// discount title, alone on the row without description
+				titleHeight := colHeight / 2
+				titleAlign := col.Align + "B"
+				if len(discountDesc) == 0 {
+					titleHeight = colHeight
+					titleAlign = col.Align
+				}
+
+				pdf.CellFormat(
+					col.Width,
+					titleHeight,
+					discountTitle,
+					"0",
+					0,
+					options.align(titleAlign),
+					false,
+					0,
+					"",
+				)
+
+				// discount desc
+				pdf.SetXY(col.X, baseY+(colHeight/2))
+				pdf.SetFont(options.fontFamily(), "", SmallTextFontSize)
+				pdf.SetTextColor(options.Theme.GreyTextColor[0], options.Theme.GreyTextColor[1], options.Theme.GreyTextColor[2])
+
+				pdf.CellFormat(
+					col.Width,
+					colHeight/2,
+					discountDesc,
+					"0",
+					0,
+					options.align(col.Align+"T"),
+					false,
+					0,
+					"",
+				)
+
+				// reset font and y
+				pdf.SetFont(options.fontFamily(), "", BaseTextFontSize)
+				pdf.SetTextColor(options.Theme.BaseTextColor[0], options.Theme.BaseTextColor[1], options.Theme.BaseTextColor[2])
+				pdf.SetY(baseY)
+			}
+
+		case ItemColumnTax:
+			// Tax
+			pdf.SetX(col.X)
+			taxes := i.taxes()
+			if len(taxes) == 0 {
+				// If no tax
+				pdf.CellFormat(
+					col.Width,
+					colHeight,
+					"--",
+					"0",
+					0,
+					options.align(col.Align),
+					false,
+					0,
+					"",
+				)
+			} else {
+				// If tax
+				var taxTitle string
+				var taxDesc string
+
+				if len(taxes) > 1 {
+					// Taxes titles one after the other, with total taxes
+					titles := make([]string, 0, len(taxes))
+					for _, tax := range taxes {
+						taxType, taxAmount := tax.getTax()
+						if taxType == "percent" {
+							titles = append(titles, fmt.Sprintf("%s %s", taxAmount, options.encodeString("%")))
+						} else {
+							titles = append(titles, fmt.Sprintf("%s %s", taxAmount, options.encodeString(currencySymbol)))
+						}
+					}
+					taxTitle = strings.Join(titles, " + ")
+					taxDesc = ac.FormatMoneyDecimal(i.taxWithDiscount(options.PricesIncludeTax))
+				} else if taxType, taxAmount := taxes[0].getTax(); taxType == "percent" {
+					taxTitle = fmt.Sprintf("%s %s", taxAmount, options.encodeString("%"))
+					// get amount from percent
+					taxDesc = ac.FormatMoneyDecimal(i.taxWithDiscount(options.PricesIncludeTax))
+				} else {
+					taxTitle = fmt.Sprintf("%s %s", taxAmount, options.encodeString(currencySymbol))
+					dCost := i.netWithDiscount(options.PricesIncludeTax)
+					if dCost.IsZero() {
+						// no percent of a zero cost
+						taxDesc = "--"
+					} else {
+						dPerc := taxAmount.Mul(decimal.NewFromFloat(100))
+						dPerc = dPerc.Div(dCost)
+						// get percent from amount
+						taxDesc = fmt.Sprintf("%s %%", dPerc.StringFixed(2))
+					}
+				}
+
+				// tax title
+				// lastY := pdf.GetY()
+				pdf.CellFormat(
+					col.Width,
+					colHeight/2,
+					taxTitle,
+					"0",
+					0,
+					options.align(col.Align+"B"),
+					false,
+					0,
+					"",
+				)
+
+				// tax desc
+				pdf.SetXY(col.X, baseY+(colHeight/2))
+				pdf.SetFont(options.fontFamily(), "", SmallTextFontSize)
+				pdf.SetTextColor(options.Theme.GreyTextColor[0], options.Theme.GreyTextColor[1], options.Theme.GreyTextColor[2])
+
+				pdf.CellFormat(
+					col.Width,
+					colHeight/2,
+					taxDesc,
+					"0",
+					0,
+					options.align(col.Align+"T"),
+					false,
+					0,
+					"",
+				)
+
+				// reset font and y
+				pdf.SetFont(options.fontFamily(), "", BaseTextFontSize)
+				pdf.SetTextColor(options.Theme.BaseTextColor[0], options.Theme.BaseTextColor[1], options.Theme.BaseTextColor[2])
+				pdf.SetY(baseY)
+			}
+
+		case ItemColumnTotalTTC:
+			// TOTAL TTC (or net total)
+			total := i.totalWithTaxAndDiscount(options.PricesIncludeTax)
+			if options.ItemsTotalColumn == ItemsTotalNet {
+				total = i.netWithDiscount(options.PricesIncludeTax)
+			}
+
+			pdf.SetX(col.X)
+			pdf.CellFormat(
+				col.Width,
+				colHeight,
+				ac.FormatMoneyDecimal(total),
+				"0",
+				0,
+				options.align(col.Align),
+				false,
+				0,
+				"",
+			)
 		}
-
-		// tax title
-		// lastY := pdf.GetY()
-		pdf.CellFormat(
-			ItemColDiscountOffset-ItemColTaxOffset,
-			colHeight/2,
-			taxTitle,
-			"0",
-			0,
-			"LB",
-			false,
-			0,
-			"",
-		)
-
-		// tax desc
-		pdf.SetXY(ItemColTaxOffset, baseY+(colHeight/2))
-		pdf.SetFont("Helvetica", "", SmallTextFontSize)
-		pdf.SetTextColor(GreyTextColor[0], GreyTextColor[1], GreyTextColor[2])
-
-		pdf.CellFormat(
-			ItemColDiscountOffset-ItemColTaxOffset,
-			colHeight/2,
-			taxDesc,
-			"0",
-			0,
-			"LT",
-			false,
-			0,
-			"",
-		)
-
-		// reset font and y
-		pdf.SetFont("Helvetica", "", BaseTextFontSize)
-		pdf.SetTextColor(BaseTextColor[0], BaseTextColor[1], BaseTextColor[2])
-		pdf.SetY(baseY)
 	}
 
-	// TOTAL TTC
-	pdf.SetX(ItemColTotalTTCOffset)
-	pdf.CellFormat(
-		190-ItemColTotalTTCOffset,
-		colHeight,
-		ac.FormatMoneyDecimal(i.totalWithTaxAndDiscount()),
-		"0",
-		0,
-		"",
-		false,
-		0,
-		"",
-	)
-
 	// Set Y for next line
 	pdf.SetY(baseY + colHeight)
 }
@@ -0,0 +1,162 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// jsonAmount decode a monetary field from a json string or number,
+// numbers being kept as written to avoid float precision loss
+type jsonAmount string
+
+// UnmarshalJSON implement json.Unmarshaler interface
+func (a *jsonAmount) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+
+		*a = jsonAmount(value)
+		return nil
+	}
+
+	if string(data) == "null" {
+		return nil
+	}
+
+	if _, err := decimal.NewFromString(string(data)); err != nil {
+		return fmt.Errorf("invalid amount %s", data)
+	}
+
+	*a = jsonAmount(data)
+	return nil
+}
+
+// UnmarshalJSON implement json.Unmarshaler interface, accepting string or number amounts
+func (i *Item) UnmarshalJSON(data []byte) error {
+	type item Item
+	aux := struct {
+		*item
+		UnitCost jsonAmount `json:"unit_cost,omitempty"`
+		Quantity jsonAmount `json:"quantity,omitempty"`
+	}{item: (*item)(i)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	i.UnitCost = string(aux.UnitCost)
+	i.Quantity = string(aux.Quantity)
+	return nil
+}
+
+// UnmarshalJSON implement json.Unmarshaler interface, accepting string or number amounts
+func (t *Tax) UnmarshalJSON(data []byte) error {
+	type tax Tax
+	aux := struct {
+		*tax
+		Percent jsonAmount `json:"percent,omitempty"`
+		Amount  jsonAmount `json:"amount,omitempty"`
+	}{tax: (*tax)(t)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	t.Percent = string(aux.Percent)
+	t.Amount = string(aux.Amount)
+	return nil
+}
+
+// UnmarshalJSON implement json.Unmarshaler interface, accepting string or number amounts
+func (t *Discount) UnmarshalJSON(data []byte) error {
+	type discount Discount
+	aux := struct {
+		*discount
+		Percent jsonAmount `json:"percent,omitempty"`
+		Amount  jsonAmount `json:"amount,omitempty"`
+	}{discount: (*discount)(t)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	t.Percent = string(aux.Percent)
+	t.Amount = string(aux.Amount)
+	return nil
+}
+
+// UnmarshalJSON implement json.Unmarshaler interface, accepting string or number amount
+func (s *Shipping) UnmarshalJSON(data []byte) error {
+	type shipping Shipping
+	aux := struct {
+		*shipping
+		Amount jsonAmount `json:"amount,omitempty"`
+	}{shipping: (*shipping)(s)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	s.Amount = string(aux.Amount)
+	return nil
+}
+
+// UnmarshalJSON implement json.Unmarshaler interface, accepting string or number amount
+func (p *Payment) UnmarshalJSON(data []byte) error {
+	type payment Payment
+	aux := struct {
+		*payment
+		Amount jsonAmount `json:"amount,omitempty"`
+	}{payment: (*payment)(p)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	p.Amount = string(aux.Amount)
+	return nil
+}
+
+// MarshalJSON implement json.Marshaler interface, leaving out zero dates which
+// omitempty does not apply to
+func (d Document) MarshalJSON() ([]byte, error) {
+	type document Document
+	aux := struct {
+		document
+		DateTime    *time.Time `json:"date_time,omitempty"`
+		DueDateTime *time.Time `json:"due_date_time,omitempty"`
+	}{document: document(d)}
+
+	if !d.DateTime.IsZero() {
+		aux.DateTime = &d.DateTime
+	}
+
+	if !d.DueDateTime.IsZero() {
+		aux.DueDateTime = &d.DueDateTime
+	}
+
+	return json.Marshal(aux)
+}
+
+// NewDocumentFromJSON return a document decoded from json, with options defaults
+func NewDocumentFromJSON(data []byte) (*Document, error) {
+	doc := &Document{}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+
+	if doc.Options == nil {
+		doc.Options = &Options{}
+	}
+
+	if err := doc.Options.setDefaults(); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
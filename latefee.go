@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// lateFeePeriodDays define the number of days of each late fee period
+var lateFeePeriodDays = map[string]int64{
+	LateFeePeriodDay:   1,
+	LateFeePeriodMonth: 30,
+	LateFeePeriodYear:  365,
+}
+
+// LateFee define interest charged on amount due once the due date is past,
+// prorated by days elapsed
+type LateFee struct {
+	Percent string    `json:"percent,omitempty" validate:"required"`                      // Interest percent per period ex 1.5
+	Period  string    `json:"period,omitempty" validate:"omitempty,oneof=day month year"` // One of day, month or year, default month
+	Date    time.Time `json:"date,omitempty"`                                             // Date interest is computed at, default now
+}
+
+func (l *LateFee) percent() decimal.Decimal {
+	percent, _ := decimal.NewFromString(l.Percent)
+	return percent
+}
+
+func (l *LateFee) date() time.Time {
+	if l.Date.IsZero() {
+		return time.Now()
+	}
+
+	return l.Date
+}
+
+// ComputeLateFee return simple interest on amount for full days elapsed from
+// dueDate to date, percent being charged per period (day, month or year, month
+// when unknown). Zero is returned when date is not past dueDate or amount is not positive.
+func ComputeLateFee(amount decimal.Decimal, percent decimal.Decimal, period string, dueDate time.Time, date time.Time) decimal.Decimal {
+	days := lateFeeDays(dueDate, date)
+	if days <= 0 || !amount.IsPositive() {
+		return decimal.NewFromFloat(0)
+	}
+
+	periodDays, ok := lateFeePeriodDays[period]
+	if !ok {
+		periodDays = lateFeePeriodDays[LateFeePeriodMonth]
+	}
+
+	return amount.Mul(percent).Mul(decimal.New(days, 0)).
+		Div(decimal.New(periodDays*100, 0))
+}
+
+// lateFeeDays return the number of full days elapsed from dueDate to date
+func lateFeeDays(dueDate time.Time, date time.Time) int64 {
+	return int64(date.Sub(dueDate).Hours() / 24)
+}
+
+// lateFeeApplies return true if document has a late fee and is overdue
+func (d *Document) lateFeeApplies() bool {
+	if d.LateFee == nil {
+		return false
+	}
+
+	dueDate, ok := d.dueDateTime()
+	return ok && lateFeeDays(dueDate, d.LateFee.date()) > 0
+}
+
+// addLateFee add interest on amount due when document is overdue
+func (d *Document) addLateFee(totals *documentTotals) *documentTotals {
+	totals.lateFee = decimal.NewFromFloat(0)
+	if !d.lateFeeApplies() {
+		return totals
+	}
+
+	dueDate, _ := d.dueDateTime()
+	totals.lateFee = d.Options.round(ComputeLateFee(totals.amountDue, d.LateFee.percent(), d.LateFee.Period, dueDate, d.LateFee.date()))
+	totals.amountDue = totals.amountDue.Add(totals.lateFee)
+
+	return totals
+}
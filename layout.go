@@ -0,0 +1,132 @@
+package generator
+
+import (
+	"strings"
+)
+
+// A4 page dimensions in mm, documents layout is designed for it
+const (
+	a4Width  float64 = 210
+	a4Height float64 = 297
+)
+
+// Rect define a rectangle position and size, in mm
+type Rect struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// Layout report the position of generated content, see BuildWithLayout
+type Layout struct {
+	FinalY     float64 `json:"final_y"`     // Y offset where content ends on last page, in mm
+	PageCount  int     `json:"page_count"`  // Number of pages
+	TotalsPage int     `json:"totals_page"` // Page of totals block, from 1
+	Totals     Rect    `json:"totals"`      // Totals block bounds on TotalsPage
+	ContentTop float64 `json:"content_top"` // Y offset where content starts, below header
+}
+
+// EstimatePageCount return the number of pages of document, laid out without
+// writing pdf output, so callers can warn about long documents up front
+func (d *Document) EstimatePageCount() (int, error) {
+	_, layout, err := d.BuildWithLayout()
+	if err != nil {
+		return 0, err
+	}
+
+	return layout.PageCount, nil
+}
+
+// marginTop return top margin of pages content, below header layout if any
+func (d *Document) marginTop() float64 {
+	if d.Header == nil || d.Header.UseCustomFunc {
+		return d.Options.MarginTop
+	}
+
+	if bottom := HeaderMarginTop + d.Header.height() + 5; bottom > d.Options.MarginTop {
+		return bottom
+	}
+
+	return d.Options.MarginTop
+}
+
+// pageSizes define supported page sizes as portrait width and height in mm
+var pageSizes = map[string][2]float64{
+	"A4":     {a4Width, a4Height},
+	"A5":     {148, 210},
+	"LETTER": {215.9, 279.4},
+	"LEGAL":  {215.9, 355.6},
+}
+
+// pageSize return page width and height in mm according to options
+func (d *Document) pageSize() (float64, float64) {
+	size, ok := pageSizes[strings.ToUpper(d.Options.PageSize)]
+	if !ok {
+		size = pageSizes["A4"]
+	}
+
+	if strings.ToUpper(d.Options.Orientation) == "L" {
+		return size[1], size[0]
+	}
+
+	return size[0], size[1]
+}
+
+// contentWidth return the usable width between margins
+func (d *Document) contentWidth() float64 {
+	width, _ := d.pageSize()
+	return width - d.Options.MarginLeft - d.Options.MarginRight
+}
+
+// endX return the x offset of a block of provided width aligned on right
+// margin, or on left margin when RTL
+func (d *Document) endX(width float64) float64 {
+	return d.mirrorX(d.rightX(width), width)
+}
+
+// rightX return the x offset of a block of provided width aligned on right margin
+func (d *Document) rightX(width float64) float64 {
+	pageWidth, _ := d.pageSize()
+	return pageWidth - d.Options.MarginRight - width
+}
+
+// mirrorX return x offset of a block of provided width, mirrored between
+// margins when RTL
+func (d *Document) mirrorX(x float64, width float64) float64 {
+	if !d.Options.RTL {
+		return x
+	}
+
+	pageWidth, _ := d.pageSize()
+	return d.Options.MarginLeft + pageWidth - d.Options.MarginRight - x - width
+}
+
+// align return cell alignment, L and R being swapped when RTL
+// (empty alignment being left)
+func (o *Options) align(align string) string {
+	if !o.RTL {
+		return align
+	}
+
+	if !strings.ContainsAny(align, "LRC") {
+		align += "R"
+	}
+
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case 'L':
+			return 'R'
+		case 'R':
+			return 'L'
+		}
+		return r
+	}, align)
+}
+
+// maxPageHeight return the maximum height for a single page, MaxPageHeight
+// being defined for A4 pages
+func (d *Document) maxPageHeight() float64 {
+	_, height := d.pageSize()
+	return height - (a4Height - MaxPageHeight)
+}
@@ -1,15 +1,9 @@
 package generator
 
 import (
-	"github.com/jung-kurt/gofpdf"
+	"time"
 )
 
-func encodeString(str string) string {
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	tr := pdf.UnicodeTranslatorFromDescriptor("")
-	return tr(str)
-}
-
 func (d *Document) typeAsString() string {
 	if d.Type == Invoice {
 		return d.Options.TextTypeInvoice
@@ -19,5 +13,116 @@ func (d *Document) typeAsString() string {
 		return d.Options.TextTypeQuotation
 	}
 
+	if d.Type == Proforma {
+		return d.Options.TextTypeProforma
+	}
+
+	if d.Type == CreditNote {
+		return d.Options.TextTypeCreditNote
+	}
+
 	return d.Options.TextTypeDeliveryNote
 }
+
+// activeItems return document items to render and sum, without zero quantity
+// items when ZeroQuantityItems is skip. Taxable items without tax are copied
+// with document default tax, so document items are never modified.
+func (d *Document) activeItems() []*Item {
+	items := make([]*Item, 0, len(d.Items))
+	for _, item := range d.Items {
+		if d.Options.ZeroQuantityItems == ZeroQuantitySkip && item.quantity().IsZero() {
+			continue
+		}
+
+		if d.DefaultTax != nil && item.Tax == nil && len(item.Taxes) == 0 && item.taxable() {
+			taxed := *item
+			taxed.Tax = d.DefaultTax
+			item = &taxed
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// itemTaxes return item taxes, document default tax being used for taxable items without tax
+func (d *Document) itemTaxes(item *Item) []*Tax {
+	if taxes := item.taxes(); len(taxes) > 0 || !item.taxable() || d.DefaultTax == nil {
+		return taxes
+	}
+
+	return []*Tax{d.DefaultTax}
+}
+
+// dateLayouts define layouts tried to parse string dates, after Options.DateFormat
+var dateLayouts = []string{"02/01/2006", "2006-01-02", time.RFC3339}
+
+// parseDate parse value with date format or common layouts
+func (o *Options) parseDate(value string) (time.Time, bool) {
+	if len(value) == 0 {
+		return time.Time{}, false
+	}
+
+	for _, layout := range append([]string{o.DateFormat}, dateLayouts...) {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// dateTime return document date from DateTime or parsed Date, today otherwise
+func (d *Document) dateTime() time.Time {
+	if !d.DateTime.IsZero() {
+		return d.DateTime
+	}
+
+	if date, ok := d.Options.parseDate(d.Date); ok {
+		return date
+	}
+
+	return time.Now()
+}
+
+// date return document date formatted with Options.DateFormat. Date strings
+// which can not be parsed are returned as is.
+func (d *Document) date() string {
+	if d.DateTime.IsZero() && len(d.Date) > 0 {
+		if _, ok := d.Options.parseDate(d.Date); !ok {
+			return d.Date
+		}
+	}
+
+	return d.dateTime().Format(d.Options.DateFormat)
+}
+
+// dueDateTime return due date from DueDateTime, parsed DueDate or document
+// date plus Options.PaymentTermDays, false when there is none
+func (d *Document) dueDateTime() (time.Time, bool) {
+	if !d.DueDateTime.IsZero() {
+		return d.DueDateTime, true
+	}
+
+	if len(d.DueDate) > 0 {
+		return d.Options.parseDate(d.DueDate)
+	}
+
+	if d.Options.PaymentTermDays <= 0 {
+		return time.Time{}, false
+	}
+
+	return d.dateTime().AddDate(0, 0, d.Options.PaymentTermDays), true
+}
+
+// dueDate return due date formatted with Options.DateFormat. Due date strings
+// which can not be parsed are returned as is.
+func (d *Document) dueDate() string {
+	date, ok := d.dueDateTime()
+	if !ok {
+		return d.DueDate
+	}
+
+	return date.Format(d.Options.DateFormat)
+}
@@ -0,0 +1,194 @@
+package generator
+
+import (
+	"strings"
+)
+
+// localizations set translated titles on options, by language code
+var localizations = map[string]func(o *Options){
+	"fr": func(o *Options) {
+		o.TextTypeInvoice = "FACTURE"
+		o.TextTypeQuotation = "DEVIS"
+		o.TextTypeDeliveryNote = "BON DE LIVRAISON"
+		o.TextTypeProforma = "FACTURE PROFORMA"
+		o.TextTypeCreditNote = "AVOIR"
+
+		o.TextRefTitle = "Réf."
+		o.TextVersionTitle = "Version"
+		o.TextDateTitle = "Date"
+		o.TextPaymentTermTitle = "Échéance"
+		o.TextDueDateTitle = "Date d'échéance"
+		o.TextShipToTitle = "Livrer à"
+		o.TextTaxIDTitle = "N° TVA"
+		o.TextRemitToTitle = "Paiement à adresser à"
+		o.TextPageTitle = "Page"
+		o.TextPageOfTitle = "sur"
+		o.TextAttachmentsTitle = "Pièces jointes"
+
+		o.TextPaymentPanelTitle = "Modalités de paiement"
+		o.TextBankAccountHolderTitle = "Titulaire du compte"
+		o.TextBankNameTitle = "Banque"
+
+		o.TextItemsNameTitle = "Désignation"
+		o.TextItemsUnitCostTitle = "Prix unitaire"
+		o.TextItemsQuantityTitle = "Qté"
+		o.TextItemsTotalHTTitle = "Total HT"
+		o.TextItemsTaxTitle = "TVA"
+		o.TextItemsDiscountTitle = "Remise"
+		o.TextItemsTotalTTCTitle = "Total TTC"
+		o.TextItemsTotalNetTitle = "Total HT"
+		o.TextItemsSummaryTitle = "Articles"
+		o.TextItemsSummaryNote = "Détail disponible sur demande"
+		o.TextItemsUngrouped = "Autres"
+		o.TextItemsSubtotal = "Sous-total"
+		o.TextItemsIncluded = "Inclus"
+
+		o.TextTotalTotal = "TOTAL HT"
+		o.TextTotalDiscounted = "TOTAL REMISÉ"
+		o.TextTotalShipping = "LIVRAISON"
+		o.TextTotalTax = "TVA"
+		o.TextTotalWithTax = "TOTAL TTC"
+		o.TextTotalTaxIncluded = "Dont TVA"
+		o.TextTotalWithholding = "RETENUE À LA SOURCE"
+		o.TextTotalAmountDue = "NET À PAYER"
+		o.TextTotalPrepaid = "ACOMPTE VERSÉ"
+		o.TextTotalBalanceDue = "RESTE À PAYER"
+		o.TextTotalInterest = "INTÉRÊTS DE RETARD"
+
+		o.TextTaxSummaryRateTitle = "Taux"
+		o.TextTaxSummaryNetTitle = "HT"
+		o.TextTaxSummaryTaxTitle = "TVA"
+		o.TextTaxSummaryGrossTitle = "TTC"
+		o.TextTaxSummaryFixedTitle = "Fixe"
+		o.TextTaxSummaryCompoundTitle = "composée"
+
+		o.TextProformaDisclaimer = "Cette facture proforma n'est pas une demande de paiement"
+	},
+	"de": func(o *Options) {
+		o.TextTypeInvoice = "RECHNUNG"
+		o.TextTypeQuotation = "ANGEBOT"
+		o.TextTypeDeliveryNote = "LIEFERSCHEIN"
+		o.TextTypeProforma = "PROFORMA-RECHNUNG"
+		o.TextTypeCreditNote = "GUTSCHRIFT"
+
+		o.TextRefTitle = "Nr."
+		o.TextVersionTitle = "Version"
+		o.TextDateTitle = "Datum"
+		o.TextPaymentTermTitle = "Zahlungsziel"
+		o.TextDueDateTitle = "Fällig am"
+		o.TextShipToTitle = "Lieferadresse"
+		o.TextTaxIDTitle = "USt-IdNr."
+		o.TextRemitToTitle = "Zahlung an"
+		o.TextPageTitle = "Seite"
+		o.TextPageOfTitle = "von"
+		o.TextAttachmentsTitle = "Anlagen"
+
+		o.TextPaymentPanelTitle = "Zahlungsinformationen"
+		o.TextBankAccountHolderTitle = "Kontoinhaber"
+		o.TextBankNameTitle = "Bank"
+
+		o.TextItemsNameTitle = "Bezeichnung"
+		o.TextItemsUnitCostTitle = "Einzelpreis"
+		o.TextItemsQuantityTitle = "Menge"
+		o.TextItemsTotalHTTitle = "Netto"
+		o.TextItemsTaxTitle = "MwSt."
+		o.TextItemsDiscountTitle = "Rabatt"
+		o.TextItemsTotalTTCTitle = "Brutto"
+		o.TextItemsTotalNetTitle = "Netto"
+		o.TextItemsSummaryTitle = "Positionen"
+		o.TextItemsSummaryNote = "Einzelaufstellung auf Anfrage"
+		o.TextItemsUngrouped = "Sonstiges"
+		o.TextItemsSubtotal = "Zwischensumme"
+		o.TextItemsIncluded = "Inklusive"
+
+		o.TextTotalTotal = "SUMME NETTO"
+		o.TextTotalDiscounted = "SUMME NACH RABATT"
+		o.TextTotalShipping = "VERSAND"
+		o.TextTotalTax = "MWST."
+		o.TextTotalWithTax = "GESAMTBETRAG"
+		o.TextTotalTaxIncluded = "Enthaltene MwSt."
+		o.TextTotalWithholding = "QUELLENSTEUER"
+		o.TextTotalAmountDue = "ZAHLBETRAG"
+		o.TextTotalPrepaid = "ANZAHLUNG"
+		o.TextTotalBalanceDue = "RESTBETRAG"
+		o.TextTotalInterest = "VERZUGSZINSEN"
+
+		o.TextTaxSummaryRateTitle = "Satz"
+		o.TextTaxSummaryNetTitle = "Netto"
+		o.TextTaxSummaryTaxTitle = "MwSt."
+		o.TextTaxSummaryGrossTitle = "Brutto"
+		o.TextTaxSummaryFixedTitle = "Fest"
+		o.TextTaxSummaryCompoundTitle = "kumuliert"
+
+		o.TextProformaDisclaimer = "Diese Proforma-Rechnung ist keine Zahlungsaufforderung"
+	},
+	"es": func(o *Options) {
+		o.TextTypeInvoice = "FACTURA"
+		o.TextTypeQuotation = "PRESUPUESTO"
+		o.TextTypeDeliveryNote = "ALBARÁN"
+		o.TextTypeProforma = "FACTURA PROFORMA"
+		o.TextTypeCreditNote = "NOTA DE CRÉDITO"
+
+		o.TextRefTitle = "Ref."
+		o.TextVersionTitle = "Versión"
+		o.TextDateTitle = "Fecha"
+		o.TextPaymentTermTitle = "Plazo de pago"
+		o.TextDueDateTitle = "Vencimiento"
+		o.TextShipToTitle = "Enviar a"
+		o.TextTaxIDTitle = "NIF-IVA"
+		o.TextRemitToTitle = "Remitir el pago a"
+		o.TextPageTitle = "Página"
+		o.TextPageOfTitle = "de"
+		o.TextAttachmentsTitle = "Adjuntos"
+
+		o.TextPaymentPanelTitle = "Forma de pago"
+		o.TextBankAccountHolderTitle = "Titular de la cuenta"
+		o.TextBankNameTitle = "Banco"
+
+		o.TextItemsNameTitle = "Concepto"
+		o.TextItemsUnitCostTitle = "Precio unitario"
+		o.TextItemsQuantityTitle = "Cant."
+		o.TextItemsTotalHTTitle = "Base imponible"
+		o.TextItemsTaxTitle = "IVA"
+		o.TextItemsDiscountTitle = "Descuento"
+		o.TextItemsTotalTTCTitle = "Total"
+		o.TextItemsTotalNetTitle = "Total neto"
+		o.TextItemsSummaryTitle = "Artículos"
+		o.TextItemsSummaryNote = "Detalle disponible bajo petición"
+		o.TextItemsUngrouped = "Otros"
+		o.TextItemsSubtotal = "Subtotal"
+		o.TextItemsIncluded = "Incluido"
+
+		o.TextTotalTotal = "BASE IMPONIBLE"
+		o.TextTotalDiscounted = "TOTAL CON DESCUENTO"
+		o.TextTotalShipping = "ENVÍO"
+		o.TextTotalTax = "IVA"
+		o.TextTotalWithTax = "TOTAL"
+		o.TextTotalTaxIncluded = "IVA incluido"
+		o.TextTotalWithholding = "RETENCIÓN"
+		o.TextTotalAmountDue = "TOTAL A PAGAR"
+		o.TextTotalPrepaid = "ANTICIPO"
+		o.TextTotalBalanceDue = "SALDO PENDIENTE"
+		o.TextTotalInterest = "INTERESES DE DEMORA"
+
+		o.TextTaxSummaryRateTitle = "Tipo"
+		o.TextTaxSummaryNetTitle = "Base"
+		o.TextTaxSummaryTaxTitle = "IVA"
+		o.TextTaxSummaryGrossTitle = "Total"
+		o.TextTaxSummaryFixedTitle = "Fijo"
+		o.TextTaxSummaryCompoundTitle = "compuesto"
+
+		o.TextProformaDisclaimer = "Esta factura proforma no es una solicitud de pago"
+	},
+}
+
+// Localize return options with titles translated in lang (en, fr, de or es), to
+// pass to New. Unknown languages and untranslated fields fall back to english defaults.
+func Localize(lang string) *Options {
+	options := &Options{}
+	if localize, ok := localizations[strings.ToLower(lang)]; ok {
+		localize(options)
+	}
+
+	return options
+}
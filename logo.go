@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"bytes"
+	"crypto/sha1"
+	b64 "encoding/base64"
+	"fmt"
+	"image"
+
+	"github.com/creasty/defaults"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// logoImageTypes map supported logo mime types to gofpdf image types
+var logoImageTypes = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpg",
+	"image/jpg":  "jpg",
+}
+
+// Logo define an image drawn with contact informations
+type Logo struct {
+	Bytes     []byte  `json:"bytes,omitempty"`                   // Image content
+	MimeType  string  `json:"mime_type,omitempty"`               // image/png or image/jpeg
+	MaxWidth  float64 `json:"max_width,omitempty" default:"70"`  // Maximum width in mm
+	MaxHeight float64 `json:"max_height,omitempty" default:"30"` // Maximum height in mm
+}
+
+// size return logo width and height scaled to fit max dimensions, unset ones
+// being defaulted on a copy, preserving image aspect ratio
+func (l Logo) size() (float64, float64, bool) {
+	if err := defaults.Set(&l); err != nil {
+		return 0, 0, false
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(l.Bytes))
+	if err != nil || config.Width == 0 || config.Height == 0 {
+		return 0, 0, false
+	}
+
+	width := l.MaxWidth
+	height := width * float64(config.Height) / float64(config.Width)
+
+	if height > l.MaxHeight {
+		height = l.MaxHeight
+		width = height * float64(config.Width) / float64(config.Height)
+	}
+
+	return width, height, true
+}
+
+// imageName return pdf image name of data, prefixed by name. Images are cached
+// by name in pdf, names being derived from content to not reuse an image of
+// another document in batches.
+func imageName(name string, data []byte) string {
+	return b64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s-%x", name, sha1.Sum(data))))
+}
+
+// appendTo draw logo at x, y using name to register image, and set y below it.
+// Empty or unsupported logos are skipped.
+func (l *Logo) appendTo(name string, x float64, y float64, pdf *gofpdf.Fpdf) {
+	if len(l.Bytes) == 0 {
+		return
+	}
+
+	imageType, ok := logoImageTypes[l.MimeType]
+	if !ok {
+		return
+	}
+
+	width, height, ok := l.size()
+	if !ok {
+		return
+	}
+
+	// Register image in pdf
+	fileName := imageName(name, l.Bytes)
+	imageOpt := gofpdf.ImageOptions{ImageType: imageType}
+	imageInfo := pdf.RegisterImageOptionsReader(fileName, imageOpt, bytes.NewReader(l.Bytes))
+
+	if imageInfo != nil && pdf.Ok() {
+		pdf.ImageOptions(fileName, x, y, width, height, false, imageOpt, 0, "")
+		pdf.SetY(y + height)
+	}
+}
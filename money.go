@@ -0,0 +1,180 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/creasty/defaults"
+	"github.com/leekchan/accounting"
+	"github.com/shopspring/decimal"
+)
+
+// currencyFormat define symbol, precision and separators of a currency
+type currencyFormat struct {
+	symbol    string
+	precision int
+	decimal   string
+	thousand  string
+}
+
+// currencyFormats by ISO 4217 code
+var currencyFormats = map[string]currencyFormat{
+	"AUD": {"$", 2, ".", ","},
+	"CAD": {"$", 2, ".", ","},
+	"CHF": {"CHF ", 2, ".", "'"},
+	"CNY": {"¥", 2, ".", ","},
+	"EUR": {"€ ", 2, ".", " "},
+	"GBP": {"£", 2, ".", ","},
+	"INR": {"Rs ", 2, ".", ","},
+	"JPY": {"¥", 0, ".", ","},
+	"KRW": {"KRW ", 0, ".", ","},
+	"RUB": {"RUB ", 2, ",", " "},
+	"SEK": {"kr ", 2, ",", " "},
+	"USD": {"$", 2, ".", ","},
+}
+
+// setDefaults fill unset currency fields from CurrencyCode, then other unset
+// fields from defaults tags
+func (o *Options) setDefaults() error {
+	format, ok := currencyFormats[strings.ToUpper(o.CurrencyCode)]
+	precisionUnset := o.CurrencyPrecision == 0
+
+	if ok {
+		if len(o.CurrencySymbol) == 0 {
+			o.CurrencySymbol = format.symbol
+		}
+		if len(o.CurrencyDecimal) == 0 {
+			o.CurrencyDecimal = format.decimal
+		}
+		if len(o.CurrencyThousand) == 0 {
+			o.CurrencyThousand = format.thousand
+		}
+	}
+
+	if err := defaults.Set(o); err != nil {
+		return err
+	}
+
+	// Zero precision can not be told from unset by defaults
+	if ok && precisionUnset {
+		o.CurrencyPrecision = format.precision
+	}
+
+	return nil
+}
+
+// withDefaults return a copy of o with defaults set, o being left untouched.
+// Nil options give default options.
+func (o *Options) withDefaults() (*Options, error) {
+	options := Options{}
+	if o != nil {
+		options = *o
+	}
+
+	if err := options.setDefaults(); err != nil {
+		return nil, err
+	}
+
+	return &options, nil
+}
+
+// moneyFormatter format amounts according to document currency options
+type moneyFormatter struct {
+	accounting.Accounting
+	grouping []int
+}
+
+func (o *Options) moneyFormatter() *moneyFormatter {
+	format := "%s" + o.CurrencySymbolSeparator + "%v"
+	if o.CurrencySymbolAfter {
+		format = "%v" + o.CurrencySymbolSeparator + "%s"
+	}
+
+	return &moneyFormatter{
+		Accounting: accounting.Accounting{
+			Symbol:    o.encodeString(o.moneySymbol(o.CurrencySymbol)),
+			Precision: o.CurrencyPrecision,
+			Thousand:  o.CurrencyThousand,
+			Decimal:   o.CurrencyDecimal,
+			Format:    format,
+		},
+		grouping: o.numberGrouping(),
+	}
+}
+
+// numberGrouping return digits group sizes, CurrencyGrouping or NumberGrouping
+// ones, none meaning the accounting library thousands grouping
+func (o *Options) numberGrouping() []int {
+	if len(o.CurrencyGrouping) > 0 {
+		return o.CurrencyGrouping
+	}
+
+	if o.NumberGrouping == NumberGroupingIndian {
+		return []int{3, 2}
+	}
+
+	return nil
+}
+
+// moneySymbol return symbol as placed in formatted amounts, its spaces being
+// trimmed when symbol is after amount or a separator is set
+func (o *Options) moneySymbol(symbol string) string {
+	if o.CurrencySymbolAfter || len(o.CurrencySymbolSeparator) > 0 {
+		return strings.TrimSpace(symbol)
+	}
+
+	return symbol
+}
+
+// FormatMoneyDecimal format value as money, using custom digits grouping if set
+func (f *moneyFormatter) FormatMoneyDecimal(value decimal.Decimal) string {
+	if len(f.grouping) == 0 {
+		return f.Accounting.FormatMoneyDecimal(value)
+	}
+
+	number := value.StringFixed(int32(f.Precision))
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign = "-"
+		number = number[1:]
+	}
+
+	integer := number
+	fraction := ""
+	if idx := strings.Index(number, "."); idx >= 0 {
+		integer = number[:idx]
+		fraction = number[idx+1:]
+	}
+
+	result := groupDigits(integer, f.grouping, f.Thousand)
+	if len(fraction) > 0 {
+		result += f.Decimal + fraction
+	}
+
+	result = strings.Replace(f.Format, "%v", result, -1)
+	return sign + strings.Replace(result, "%s", f.Symbol, -1)
+}
+
+// groupDigits split digits in groups from right to left.
+// Each entry of grouping is the size of a group, the last one is repeated,
+// ex []int{3, 2} will output 12,34,567
+func groupDigits(digits string, grouping []int, separator string) string {
+	var groups []string
+	groupIndex := 0
+
+	for len(digits) > 0 {
+		size := grouping[groupIndex]
+		if groupIndex < len(grouping)-1 {
+			groupIndex++
+		}
+
+		if size <= 0 || size >= len(digits) {
+			groups = append([]string{digits}, groups...)
+			break
+		}
+
+		groups = append([]string{digits[len(digits)-size:]}, groups...)
+		digits = digits[:len(digits)-size]
+	}
+
+	return strings.Join(groups, separator)
+}
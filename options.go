@@ -1,23 +1,104 @@
 package generator
 
+import (
+	"github.com/shopspring/decimal"
+)
+
 // Options for Document
 type Options struct {
-	AutoPrint bool `json:"auto_print,omitempty"`
+	PageSize    string       `default:"A4" json:"page_size,omitempty"`       // One of A4, A5, Letter, Legal
+	Orientation string       `default:"P" json:"orientation,omitempty"`      // P (portrait) or L (landscape)
+	Watermark   string       `json:"watermark,omitempty"`                    // Text drawn diagonally behind content of every page, ex PAID, DRAFT, VOID
+	Font        *Font        `json:"font,omitempty"`                         // Custom TTF font for non-Latin scripts, default to Helvetica
+	RTL         bool         `json:"rtl,omitempty"`                          // Mirror layout for right-to-left languages
+	Theme       Theme        `json:"theme,omitempty"`                        // Document colors
+	PaymentQR   *PaymentQR   `json:"payment_qr,omitempty"`                   // Render an EPC QR code for SEPA transfer
+	ItemColumns []ItemColumn `json:"item_columns,omitempty" validate:"dive"` // Items table columns in order, default to all columns
+
+	AutoPrint           bool `json:"auto_print,omitempty"`
+	XMPMetadata         bool `json:"xmp_metadata,omitempty"`          // Embed ref, date, totals breakdown and currency as xmp metadata
+	ShowAttachmentsList bool `json:"show_attachments_list,omitempty"` // Render attachments file names on last page
+	LogoOnEveryPage     bool `json:"logo_on_every_page,omitempty"`    // Repeat company logo in header of every page
+	PadToEvenPages      bool `json:"pad_to_even_pages,omitempty"`     // Add a blank page when document ends on an odd page (duplex)
+	ShowPageNumbers     bool `json:"show_page_numbers,omitempty"`     // Print "Page X of Y" centered in footer
+	ShowAmountInWords   bool `json:"show_amount_in_words,omitempty"`  // Print amount due spelled out below totals, see AmountInWords
+	SwapPartyColumns    bool `json:"swap_party_columns,omitempty"`    // Draw company on the right, below metas, and customer on the left
+
+	SummaryItemsThreshold int     `json:"summary_items_threshold,omitempty"`                // Above this items count, render items totals grouped by tax only
+	ItemsCellPadding      float64 `json:"items_cell_padding,omitempty"`                     // Horizontal padding of items table cells, in mm
+	NotesMode             string  `default:"clamp" json:"notes_mode,omitempty"`             // One of clamp, flow-after-totals, separate-page
+	NotesFullWidth        bool    `json:"notes_full_width,omitempty"`                       // Render clamp notes on full content width, below totals
+	NotesPosition         string  `json:"notes_position,omitempty"`                         // One of beside-totals, below-totals, above-totals, default from NotesMode and NotesFullWidth
+	NotesFontSize         float64 `default:"9" json:"notes_font_size,omitempty"`            // Notes font size, in points
+	ItemsTotalColumn      string  `default:"gross" json:"items_total_column,omitempty"`     // Items last column, one of gross, net
+	ZeroQuantityItems     string  `default:"show" json:"zero_quantity_items,omitempty"`     // One of show, skip, included
+	QuantityPrecision     int     `json:"quantity_precision,omitempty"`                     // Decimals of printed quantities, quantities being printed as entered when 0
+	PricesIncludeTax      bool    `json:"prices_include_tax,omitempty"`                     // Items prices include tax, tax is extracted from items and totals
+	VersionDisplay        string  `default:"reserve" json:"version_display,omitempty"`      // One of reserve, hide
+	TableContinuedLabel   string  `json:"table_continued_label,omitempty"`                  // Note printed above items table titles repeated on next pages, ex "(continued)"
+	ShowItemIndex         bool    `json:"show_item_index,omitempty"`                        // Prepend a row number column to items table
+	AlternateRowColors    bool    `json:"alternate_row_colors,omitempty"`                   // Fill every other item row with Theme.AltRowBgColor
+	TableBorders          string  `default:"none" json:"table_borders,omitempty"`           // Items table borders, one of none, horizontal, full
+	AutoHideEmptyColumns  bool    `json:"auto_hide_empty_columns,omitempty"`                // Leave out tax and discount columns no item fills, and totals tax row when zero
+	GroupItems            bool    `json:"group_items,omitempty"`                            // Render items under their Group heading, with a subtotal after each group
+	StrictDiscounts       bool    `json:"strict_discounts,omitempty"`                       // Reject document amount discounts greater than items total, instead of clamping them
+	ClampPercents         bool    `json:"clamp_percents,omitempty"`                         // Clamp taxes and discounts percents between 0 and 100, instead of rejecting them
+	StrictTaxIDs          bool    `json:"strict_tax_ids,omitempty"`                         // Reject company and customer EU VAT identification numbers with malformed format
+	ShowDiscountAmount    bool    `json:"show_discount_amount,omitempty"`                   // Show percent items discounts saving as currency amount under the percent
+	PaymentTermDays       int     `json:"payment_term_days,omitempty"`                      // Compute due date from date when DueDate is empty
+	DateFormat            string  `default:"02/01/2006" json:"date_format,omitempty"`       // Go time layout of printed dates
+	RefFormat             string  `json:"ref_format,omitempty"`                             // Ref template of SetRefSeq, ex INV-{year}-{seq:06d}, see FormatRef
+	RoundingMode          string  `default:"none" json:"rounding_mode,omitempty"`           // One of none, half-up, bankers, applied to totals
+	RoundPerLine          bool    `json:"round_per_line,omitempty"`                         // Round items totals and taxes with RoundingMode, half up when none, before summation
+	WithholdingBase       string  `default:"net" json:"withholding_base,omitempty"`         // Withholding tax base, one of net, gross
+	ItemImageSize         float64 `default:"12" json:"item_image_size,omitempty"`           // Items images max width and height, in mm
+	MarginLeft            float64 `default:"10" json:"margin_left,omitempty"`               // Page left margin, in mm
+	MarginRight           float64 `default:"10" json:"margin_right,omitempty"`              // Page right margin, in mm
+	MarginTop             float64 `default:"20" json:"margin_top,omitempty"`                // Page top margin, in mm
+	MaxAttachmentSize     int     `default:"10485760" json:"max_attachment_size,omitempty"` // Max size of each attachment, in bytes
+	TitleBoxWidth         float64 `default:"80" json:"title_box_width,omitempty"`           // Min width of document title box, in mm, grown to fit title
+	TitleBackground       []byte  `json:"title_background,omitempty"`                       // PNG or JPEG image covering title box, instead of Theme.DarkBgColor
+
+	CurrencyCode            string `json:"currency_code,omitempty"` // ISO 4217 code, set unset currency symbol, precision and separators, ex JPY
+	CurrencySymbol          string `default:"€ " json:"currency_symbol,omitempty"`
+	CurrencySymbolAfter     bool   `json:"currency_symbol_after,omitempty"`     // Place symbol after amount, ex 1 234,56 €
+	CurrencySymbolSeparator string `json:"currency_symbol_separator,omitempty"` // Between amount and symbol, symbol spaces being trimmed when set
+	CurrencyPrecision       int    `default:"2" json:"currency_precision,omitempty"`
+	CurrencyDecimal         string `default:"." json:"currency_decimal,omitempty"`
+	CurrencyThousand        string `default:"," json:"currency_thousand,omitempty"`
+	CurrencyGrouping        []int  `json:"currency_grouping,omitempty"`                 // Digits group sizes from right, last one repeated, ex [3, 2] for 12,34,567
+	NumberGrouping          string `default:"western" json:"number_grouping,omitempty"` // One of western (1,234,567), indian (12,34,567), when CurrencyGrouping is unset
 
-	CurrencySymbol    string `default:"€ " json:"currency_symbol,omitempty"`
-	CurrencyPrecision int    `default:"2" json:"currency_precision,omitempty"`
-	CurrencyDecimal   string `default:"." json:"currency_decimal,omitempty"`
-	CurrencyThousand  string `default:" " json:"currency_thousand,omitempty"`
+	ExchangeRates map[string]decimal.Decimal `json:"exchange_rates,omitempty"` // Document currency value of one unit of items currencies, by item currency
 
 	TextTypeInvoice      string `default:"INVOICE" json:"text_type_invoice,omitempty"`
 	TextTypeQuotation    string `default:"QUOTATION" json:"text_type_quotation,omitempty"`
 	TextTypeDeliveryNote string `default:"DELIVERY NOTE" json:"text_type_delivery_note,omitempty"`
+	TextTypeProforma     string `default:"PROFORMA INVOICE" json:"text_type_proforma,omitempty"`
+	TextTypeCreditNote   string `default:"CREDIT NOTE" json:"text_type_credit_note,omitempty"`
 
 	TextRefTitle         string `default:"Ref." json:"text_ref_title,omitempty"`
 	TextVersionTitle     string `default:"Version" json:"text_version_title,omitempty"`
 	TextDateTitle        string `default:"Date" json:"text_date_title,omitempty"`
 	TextPaymentTermTitle string `default:"Payment term" json:"text_payment_term_title,omitempty"`
+	TextDueDateTitle     string `default:"Due date" json:"text_due_date_title,omitempty"`
+	TextShipToTitle      string `default:"Ship to" json:"text_ship_to_title,omitempty"`
+	TextFromTitle        string `json:"text_from_title,omitempty"` // Heading above company, ex "From"
+	TextToTitle          string `json:"text_to_title,omitempty"`   // Heading above customer, ex "Bill to"
+	TextRemitToTitle     string `default:"Remit payment to" json:"text_remit_to_title,omitempty"`
+	TextTaxIDTitle       string `default:"VAT ID" json:"text_tax_id_title,omitempty"`
+	TextPageTitle        string `default:"Page" json:"text_page_title,omitempty"`
+	TextPageOfTitle      string `default:"of" json:"text_page_of_title,omitempty"`
+	TextAttachmentsTitle string `default:"Attachments" json:"text_attachments_title,omitempty"`
+	TextBlankPage        string `json:"text_blank_page,omitempty"` // Note on duplex blank page, ex "This page intentionally left blank"
 
+	TextPaymentPanelTitle      string `default:"How to pay" json:"text_payment_panel_title,omitempty"`
+	TextBankAccountHolderTitle string `default:"Account holder" json:"text_bank_account_holder_title,omitempty"`
+	TextBankNameTitle          string `default:"Bank" json:"text_bank_name_title,omitempty"`
+	TextBankIBANTitle          string `default:"IBAN" json:"text_bank_iban_title,omitempty"`
+	TextBankBICTitle           string `default:"BIC" json:"text_bank_bic_title,omitempty"`
+
+	TextItemsIndexTitle    string `default:"#" json:"text_items_index_title,omitempty"`
 	TextItemsNameTitle     string `default:"Name" json:"text_items_name_title,omitempty"`
 	TextItemsUnitCostTitle string `default:"Unit price" json:"text_items_unit_cost_title,omitempty"`
 	TextItemsQuantityTitle string `default:"Qty" json:"text_items_quantity_title,omitempty"`
@@ -25,9 +106,35 @@ type Options struct {
 	TextItemsTaxTitle      string `default:"Tax" json:"text_items_tax_title,omitempty"`
 	TextItemsDiscountTitle string `default:"Discount" json:"text_items_discount_title,omitempty"`
 	TextItemsTotalTTCTitle string `default:"Total" json:"text_items_total_ttc_title,omitempty"`
+	TextItemsTotalNetTitle string `default:"Net total" json:"text_items_total_net_title,omitempty"`
+	TextItemsSummaryTitle  string `default:"Items" json:"text_items_summary_title,omitempty"`
+	TextItemsSummaryNote   string `default:"Itemized detail available on request" json:"text_items_summary_note,omitempty"`
+	TextItemsIncluded      string `default:"Included" json:"text_items_included,omitempty"`
+	TextItemsUngrouped     string `default:"Other" json:"text_items_ungrouped,omitempty"`
+	TextItemsSubtotal      string `default:"Subtotal" json:"text_items_subtotal,omitempty"`
+
+	TextTotalTotal       string `default:"TOTAL" json:"text_total_total,omitempty"`
+	TextTotalDiscounted  string `default:"TOTAL DISCOUNTED" json:"text_total_discounted,omitempty"`
+	TextTotalShipping    string `default:"SHIPPING" json:"text_total_shipping,omitempty"`
+	TextTotalTax         string `default:"TAX" json:"text_total_tax,omitempty"`
+	TextTotalWithTax     string `default:"TOTAL WITH TAX" json:"text_total_with_tax,omitempty"`
+	TextTotalZero        string `json:"text_total_zero,omitempty"` // Replace total with tax amount when zero, ex "NO PAYMENT DUE"
+	TextTotalTaxIncluded string `default:"Total includes tax of" json:"text_total_tax_included,omitempty"`
+	TextTotalWithholding string `default:"WITHHOLDING" json:"text_total_withholding,omitempty"`
+	TextTotalAmountDue   string `default:"AMOUNT DUE" json:"text_total_amount_due,omitempty"`
+	TextTotalPrepaid     string `default:"LESS DEPOSIT" json:"text_total_prepaid,omitempty"`
+	TextTotalBalanceDue  string `default:"BALANCE DUE" json:"text_total_balance_due,omitempty"`
+	TextTotalInterest    string `default:"INTEREST" json:"text_total_interest,omitempty"`
+
+	TextTotalsSingleCurrency string `default:"Totals assume a single settlement currency" json:"text_totals_single_currency,omitempty"`
+	TextTotalsExchangeRates  string `default:"Totals converted at" json:"text_totals_exchange_rates,omitempty"`
+
+	TextTaxSummaryRateTitle     string `default:"Rate" json:"text_tax_summary_rate_title,omitempty"`
+	TextTaxSummaryNetTitle      string `default:"Net" json:"text_tax_summary_net_title,omitempty"`
+	TextTaxSummaryTaxTitle      string `default:"Tax" json:"text_tax_summary_tax_title,omitempty"`
+	TextTaxSummaryGrossTitle    string `default:"Gross" json:"text_tax_summary_gross_title,omitempty"`
+	TextTaxSummaryFixedTitle    string `default:"Fixed" json:"text_tax_summary_fixed_title,omitempty"`
+	TextTaxSummaryCompoundTitle string `default:"compound" json:"text_tax_summary_compound_title,omitempty"`
 
-	TextTotalTotal      string `default:"TOTAL" json:"text_total_total,omitempty"`
-	TextTotalDiscounted string `default:"TOTAL DISCOUNTED" json:"text_total_discounted,omitempty"`
-	TextTotalTax        string `default:"TAX" json:"text_total_tax,omitempty"`
-	TextTotalWithTax    string `default:"TOTAL WITH TAX" json:"text_total_with_tax,omitempty"`
+	TextProformaDisclaimer string `default:"This proforma invoice is not a request for payment" json:"text_proforma_disclaimer,omitempty"`
 }
@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"bytes"
+	"io"
+)
+
+// BuildToBytes build pdf document and return its content
+func (d *Document) BuildToBytes() ([]byte, error) {
+	pdf, err := d.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// WriteTo build pdf document and write it to w, implementing io.WriterTo
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	pdf, err := d.Build()
+	if err != nil {
+		return 0, err
+	}
+
+	cw := &countingWriter{w: w}
+	err = pdf.Output(cw)
+
+	return cw.n, err
+}
+
+// countingWriter count bytes written to underlying writer
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// BankDetails define bank account informations for payment by transfer
+type BankDetails struct {
+	AccountHolder string `json:"account_holder,omitempty"`
+	BankName      string `json:"bank_name,omitempty"`
+	IBAN          string `json:"iban,omitempty" validate:"required"`
+	BIC           string `json:"bic,omitempty"`
+}
+
+// normalizeIBAN return IBAN without spaces, upper cased
+func normalizeIBAN(iban string) string {
+	return strings.ToUpper(strings.Replace(iban, " ", "", -1))
+}
+
+// validateIBAN check IBAN length (between 15 and 34 characters), format
+// (country code, check digits, alphanumeric) and mod-97 checksum
+func validateIBAN(iban string) error {
+	iban = normalizeIBAN(iban)
+
+	if length := len(iban); length < 15 || length > 34 {
+		return ErrInvalidIBANLength
+	}
+
+	for index, r := range iban {
+		isDigit := r >= '0' && r <= '9'
+		isLetter := r >= 'A' && r <= 'Z'
+
+		if (index < 2 && !isLetter) || (index >= 2 && index < 4 && !isDigit) || (!isDigit && !isLetter) {
+			return ErrInvalidIBANFormat
+		}
+	}
+
+	// Move country code and check digits to the end, convert letters to numbers
+	// (A = 10 ... Z = 35) and compute remainder digit by digit
+	remainder := 0
+	for _, r := range iban[4:] + iban[:4] {
+		if r >= 'A' && r <= 'Z' {
+			remainder = (remainder*100 + int(r-'A') + 10) % 97
+		} else {
+			remainder = (remainder*10 + int(r-'0')) % 97
+		}
+	}
+
+	if remainder != 1 {
+		return ErrInvalidIBANChecksum
+	}
+
+	return nil
+}
+
+// validate check bank details IBAN
+func (b *BankDetails) validate() error {
+	return validateIBAN(b.IBAN)
+}
+
+// paymentPanelHeight define the height of the "how to pay" panel
+const paymentPanelHeight float64 = 32
+
+// appendPaymentPanel draw a bordered "how to pay" panel with bank details on the left
+// and payment qr code on the right
+func (d *Document) appendPaymentPanel(pdf *gofpdf.Fpdf) {
+	if d.BankDetails == nil {
+		return
+	}
+
+	// Check page height
+	y := pdf.GetY() + 12
+	if y+paymentPanelHeight > d.maxPageHeight() {
+		pdf.AddPage()
+		y = pdf.GetY()
+	}
+
+	// Draw border
+	pdf.SetDrawColor(d.Options.Theme.DarkBgColor[0], d.Options.Theme.DarkBgColor[1], d.Options.Theme.DarkBgColor[2])
+	pdf.Rect(d.Options.MarginLeft, y, d.contentWidth(), paymentPanelHeight, "D")
+	pdf.SetDrawColor(0, 0, 0)
+
+	// Title
+	pdf.SetXY(d.Options.MarginLeft+2, y+2)
+	pdf.SetFont(d.Options.fontFamily(), "B", LargeTextFontSize)
+	pdf.CellFormat(100, 6, d.Options.encodeString(d.Options.TextPaymentPanelTitle), "0", 0, "L", false, 0, "")
+
+	// Bank details
+	d.BankDetails.appendLines(d.Options, d.Options.MarginLeft+2, y+9, pdf)
+
+	// Payment qr code
+	if d.Options.PaymentQR != nil {
+		d.appendPaymentQR(pdf, d.Options.MarginLeft+d.contentWidth()-paymentQRSize-3, y+3)
+	}
+
+	pdf.SetFont(d.Options.fontFamily(), "", BaseTextFontSize)
+	pdf.SetY(y + paymentPanelHeight)
+}
+
+// appendLines draw bank details as "title: value" lines from x, y
+func (b *BankDetails) appendLines(options *Options, x float64, y float64, pdf *gofpdf.Fpdf) {
+	lines := [][2]string{
+		{options.TextBankAccountHolderTitle, b.AccountHolder},
+		{options.TextBankNameTitle, b.BankName},
+		{options.TextBankIBANTitle, b.IBAN},
+		{options.TextBankBICTitle, b.BIC},
+	}
+
+	pdf.SetXY(x, y)
+	pdf.SetFont(options.fontFamily(), "", BaseTextFontSize)
+
+	for _, line := range lines {
+		if len(line[1]) == 0 {
+			continue
+		}
+
+		pdf.SetX(x)
+		pdf.CellFormat(120, 5, options.encodeString(fmt.Sprintf("%s: %s", line[0], line[1])), "0", 1, "L", false, 0, "")
+	}
+}
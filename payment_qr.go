@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/shopspring/decimal"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// PaymentQR define a SEPA credit transfer rendered as an EPC QR code
+type PaymentQR struct {
+	IBAN   string `json:"iban,omitempty"`
+	BIC    string `json:"bic,omitempty"`
+	Name   string `json:"name,omitempty"`   // Beneficiary name
+	Amount string `json:"amount,omitempty"` // Amount in EUR, default to document amount due
+}
+
+// paymentQRSize define the size of the payment qr code in mm
+const paymentQRSize float64 = 26
+
+// iban return IBAN without spaces, upper cased
+func (q *PaymentQR) iban() string {
+	return normalizeIBAN(q.IBAN)
+}
+
+// paymentQRNameMaxLength define the max length of the beneficiary name in EPC069-12
+const paymentQRNameMaxLength int = 70
+
+// validate check payment qr IBAN and beneficiary name
+func (q *PaymentQR) validate() error {
+	if err := validateIBAN(q.IBAN); err != nil {
+		return err
+	}
+
+	if length := utf8.RuneCountInString(strings.TrimSpace(q.Name)); length == 0 || length > paymentQRNameMaxLength {
+		return &FieldError{Field: "Options.PaymentQR.Name", Err: ErrPaymentQRName}
+	}
+
+	return nil
+}
+
+// amount return PaymentQR amount, or amountDue when it is empty or zero
+func (q *PaymentQR) amount(amountDue decimal.Decimal) decimal.Decimal {
+	if qrAmount, err := decimal.NewFromString(q.Amount); err == nil && !qrAmount.IsZero() {
+		return qrAmount
+	}
+
+	return amountDue
+}
+
+// validatePaymentQR check payment qr IBAN, and that it transfers a positive
+// amount in EUR, the only currency of SEPA credit transfers
+func (d *Document) validatePaymentQR() error {
+	if err := d.Options.PaymentQR.validate(); err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(d.Options.CurrencyCode, "EUR") {
+		return &FieldError{Field: "Options.PaymentQR", Err: ErrPaymentQRCurrency}
+	}
+
+	if !d.Options.PaymentQR.amount(d.computeTotals().amountDue).IsPositive() {
+		return &FieldError{Field: "Options.PaymentQR", Err: ErrPaymentQRAmount}
+	}
+
+	return nil
+}
+
+// epcPayload return EPC069-12 formatted credit transfer, using amount when
+// PaymentQR amount is empty or zero and text as remittance information
+func (q *PaymentQR) epcPayload(amount decimal.Decimal, text string) string {
+	amount = q.amount(amount)
+
+	lines := []string{
+		"BCD",
+		"002",
+		"1",
+		"SCT",
+		strings.ToUpper(strings.Replace(q.BIC, " ", "", -1)),
+		q.Name,
+		q.iban(),
+		"EUR" + amount.StringFixed(2),
+		"",
+		"",
+		text,
+	}
+
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// appendPaymentQR draw payment qr code with its top left corner at x, y
+func (d *Document) appendPaymentQR(pdf *gofpdf.Fpdf, x float64, y float64) {
+	payload := d.Options.PaymentQR.epcPayload(d.computeTotals().amountDue, d.Ref)
+
+	png, err := qrcode.Encode(payload, qrcode.Medium, 256)
+	if err != nil {
+		pdf.SetError(err)
+		return
+	}
+
+	fileName := imageName("payment-qr", png)
+	imageOpt := gofpdf.ImageOptions{ImageType: "png"}
+	pdf.RegisterImageOptionsReader(fileName, imageOpt, bytes.NewReader(png))
+	pdf.ImageOptions(fileName, x, y, paymentQRSize, paymentQRSize, false, imageOpt, 0, "")
+}
+
+// appendStandalonePaymentQR draw payment qr code below payment term, when
+// there is no payment panel to hold it
+func (d *Document) appendStandalonePaymentQR(pdf *gofpdf.Fpdf) {
+	if d.Options.PaymentQR == nil || d.BankDetails != nil {
+		return
+	}
+
+	y := pdf.GetY() + 10
+	if y+paymentQRSize > d.maxPageHeight() {
+		pdf.AddPage()
+		y = pdf.GetY()
+	}
+
+	d.appendPaymentQR(pdf, d.endX(paymentQRSize), y)
+	pdf.SetY(y + paymentQRSize)
+}
@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// Payment define an amount already paid, ex a deposit, subtracted from amount due
+type Payment struct {
+	Label  string `json:"label,omitempty"` // Payment reason ex Deposit
+	Date   string `json:"date,omitempty"`
+	Amount string `json:"amount,omitempty" validate:"required"`
+}
+
+func (p *Payment) amount() decimal.Decimal {
+	amount, _ := decimal.NewFromString(p.Amount)
+	return amount
+}
+
+// amountDueRow define a totals row drawn below total with tax
+type amountDueRow struct {
+	title  string
+	amount decimal.Decimal
+}
+
+// amountDueRows return withholding, payments and late fee rows followed by the
+// amount due row, or none when there is neither. Proformas, which do not request
+// payment, have none.
+func (d *Document) amountDueRows(totals *documentTotals) []amountDueRow {
+	var rows []amountDueRow
+	if d.Type == Proforma {
+		return rows
+	}
+
+	if d.Withholding != nil {
+		rows = append(rows, amountDueRow{d.Options.TextTotalWithholding, totals.withholding.Neg()})
+	}
+
+	if len(d.Payments) > 0 {
+		rows = append(rows, amountDueRow{d.Options.TextTotalPrepaid, totals.prepaid.Neg()})
+	}
+
+	if d.lateFeeApplies() {
+		rows = append(rows, amountDueRow{d.Options.TextTotalInterest, totals.lateFee})
+	}
+
+	if len(rows) == 0 {
+		return rows
+	}
+
+	if len(d.Payments) > 0 {
+		return append(rows, amountDueRow{d.Options.TextTotalBalanceDue, totals.amountDue})
+	}
+
+	return append(rows, amountDueRow{d.Options.TextTotalAmountDue, totals.amountDue})
+}
+
+// addPayments subtract payments sum from amount due
+func (d *Document) addPayments(totals *documentTotals) *documentTotals {
+	totals.prepaid = decimal.NewFromFloat(0)
+	for _, payment := range d.Payments {
+		totals.prepaid = totals.prepaid.Add(payment.amount())
+	}
+
+	totals.prepaid = d.Options.round(totals.prepaid)
+	totals.amountDue = totals.amountDue.Sub(totals.prepaid)
+
+	return totals
+}
@@ -0,0 +1,9 @@
+//go:build !pdftoppm
+// +build !pdftoppm
+
+package generator
+
+// rasterizeFirstPage is not available without a rasterizer
+func rasterizeFirstPage(content []byte, dpi float64) ([]byte, error) {
+	return nil, ErrRasterizerUnavailable
+}
@@ -0,0 +1,35 @@
+//go:build pdftoppm
+// +build pdftoppm
+
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+)
+
+// rasterizeFirstPage render first page of pdf content as png using poppler pdftoppm
+func rasterizeFirstPage(content []byte, dpi float64) ([]byte, error) {
+	cmd := exec.Command(
+		"pdftoppm",
+		"-png",
+		"-r", strconv.FormatFloat(dpi, 'f', -1, 64),
+		"-f", "1",
+		"-l", "1",
+		"-singlefile",
+		"-", "-",
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(content)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("pdftoppm: %v: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// refPlaceholder match ref format placeholders, ex {year} or {seq:06d}
+var refPlaceholder = regexp.MustCompile(`\{(year|yy|month|day|seq)(?::(0?[1-9][0-9]*)d)?\}`)
+
+// FormatRef return a document ref from format, replacing {year}, {yy}, {month},
+// {day} with t and {seq} with seq. Placeholders accept a printf like width,
+// ex {seq:06d} for 000042. Unknown placeholders are kept as is.
+func FormatRef(format string, seq int, t time.Time) string {
+	return refPlaceholder.ReplaceAllStringFunc(format, func(placeholder string) string {
+		match := refPlaceholder.FindStringSubmatch(placeholder)
+
+		var value int
+		width := "1"
+		switch match[1] {
+		case "year":
+			value = t.Year()
+		case "yy":
+			value, width = t.Year()%100, "02"
+		case "month":
+			value, width = int(t.Month()), "02"
+		case "day":
+			value, width = t.Day(), "02"
+		case "seq":
+			value = seq
+		}
+
+		if len(match[2]) > 0 {
+			width = match[2]
+		}
+
+		return fmt.Sprintf("%"+width+"d", value)
+	})
+}
@@ -0,0 +1,25 @@
+package generator
+
+import (
+	"github.com/jung-kurt/gofpdf"
+)
+
+// RenderTotals render only the document totals block into pdf, with its top
+// left corner at x, y. Pdf must have a current page.
+func (d *Document) RenderTotals(pdf *gofpdf.Fpdf, x float64, y float64) error {
+	options, err := d.Options.withDefaults()
+	if err != nil {
+		return err
+	}
+
+	doc := *d
+	doc.Options = options
+
+	if doc.Options.Font != nil {
+		doc.Options.Font.register(pdf)
+	}
+
+	doc.drawTotals(pdf, x, y)
+
+	return pdf.Error()
+}
@@ -0,0 +1,22 @@
+package generator
+
+import (
+	"fmt"
+)
+
+// RenderPNG build pdf document and rasterize its first page as png at dpi
+// resolution, ex for thumbnails. Rasterization requires building with the
+// pdftoppm tag and poppler pdftoppm binary in PATH, ErrRasterizerUnavailable
+// is returned otherwise.
+func (d *Document) RenderPNG(dpi float64) ([]byte, error) {
+	if dpi <= 0 {
+		return nil, fmt.Errorf("invalid dpi %v", dpi)
+	}
+
+	content, err := d.BuildToBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	return rasterizeFirstPage(content, dpi)
+}
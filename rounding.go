@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// RoundingWarning describe a difference between the sum of displayed (rounded)
+// items totals and the displayed total
+type RoundingWarning struct {
+	LinesTotal decimal.Decimal // Sum of rounded items totals
+	Total      decimal.Decimal // Rounded document total
+	Difference decimal.Decimal // Total - LinesTotal
+}
+
+// String implement Stringer interface
+func (w *RoundingWarning) String() string {
+	return fmt.Sprintf(
+		"items totals sum to %s but total is %s (difference %s)",
+		w.LinesTotal.String(),
+		w.Total.String(),
+		w.Difference.String(),
+	)
+}
+
+// CheckRounding compare the sum of displayed items totals with the displayed
+// total, and return a warning if they differ by more than the currency smallest
+// unit. Nil is returned when there is no drift.
+func (d *Document) CheckRounding() *RoundingWarning {
+	precision := int32(d.Options.CurrencyPrecision)
+	smallestUnit := decimal.New(1, -precision)
+
+	totals := d.computeTotals()
+
+	// Items last column show net or gross totals, before document discount
+	total := totals.totalGross
+	if d.Options.ItemsTotalColumn == ItemsTotalNet {
+		total = totals.total
+	}
+
+	linesTotal := decimal.NewFromFloat(0)
+	for _, item := range d.convertedItems(d.activeItems()) {
+		lineTotal := item.totalWithTaxAndDiscount(d.Options.PricesIncludeTax)
+		if d.Options.ItemsTotalColumn == ItemsTotalNet {
+			lineTotal = item.netWithDiscount(d.Options.PricesIncludeTax)
+		}
+
+		linesTotal = linesTotal.Add(lineTotal.Round(precision))
+	}
+
+	total = total.Round(precision)
+	difference := total.Sub(linesTotal)
+
+	if difference.Abs().LessThanOrEqual(smallestUnit) {
+		return nil
+	}
+
+	return &RoundingWarning{
+		LinesTotal: linesTotal,
+		Total:      total,
+		Difference: difference,
+	}
+}
+
+// round value to currency precision according to rounding mode
+func (o *Options) round(value decimal.Decimal) decimal.Decimal {
+	precision := int32(o.CurrencyPrecision)
+
+	switch o.RoundingMode {
+	case RoundingModeHalfUp:
+		return value.Round(precision)
+	case RoundingModeBankers:
+		return value.RoundBank(precision)
+	}
+
+	return value
+}
+
+// roundLine round an item amount before summation when RoundPerLine is set,
+// half up when there is no rounding mode
+func (o *Options) roundLine(value decimal.Decimal) decimal.Decimal {
+	if !o.RoundPerLine {
+		return value
+	}
+
+	if o.RoundingMode == RoundingModeNone || len(o.RoundingMode) == 0 {
+		return value.Round(int32(o.CurrencyPrecision))
+	}
+
+	return o.round(value)
+}
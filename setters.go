@@ -1,5 +1,12 @@
 package generator
 
+import (
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
 // SetType set type of document
 func (d *Document) SetType(docType string) *Document {
 	d.Type = docType
@@ -24,6 +31,19 @@ func (d *Document) SetRef(ref string) *Document {
 	return d
 }
 
+// SetRefSeq set document ref from sequence number and document date, formatted
+// with Options.RefFormat
+func (d *Document) SetRefSeq(seq int) *Document {
+	d.Ref = FormatRef(d.Options.RefFormat, seq, d.dateTime())
+	return d
+}
+
+// SetClientRef of document
+func (d *Document) SetClientRef(clientRef string) *Document {
+	d.ClientRef = clientRef
+	return d
+}
+
 // SetVersion of document
 func (d *Document) SetVersion(version string) *Document {
 	d.Version = version
@@ -54,24 +74,91 @@ func (d *Document) SetCustomer(customer *Contact) *Document {
 	return d
 }
 
+// SetRemitTo of document
+func (d *Document) SetRemitTo(remitTo *Contact) *Document {
+	d.RemitTo = remitTo
+	return d
+}
+
+// SetFooterMessage of document
+func (d *Document) SetFooterMessage(message string) *Document {
+	d.FooterMessage = message
+	return d
+}
+
+// SetShipTo of document
+func (d *Document) SetShipTo(shipTo *Contact) *Document {
+	d.ShipTo = shipTo
+	return d
+}
+
 // AppendItem to document items
 func (d *Document) AppendItem(item *Item) *Document {
 	d.Items = append(d.Items, item)
 	return d
 }
 
+// AddItem to document items, same as AppendItem
+func (d *Document) AddItem(item *Item) *Document {
+	return d.AppendItem(item)
+}
+
+// SortItems of document, items being ordered with less function
+func (d *Document) SortItems(less func(a *Item, b *Item) bool) *Document {
+	sort.SliceStable(d.Items, func(i, j int) bool {
+		return less(d.Items[i], d.Items[j])
+	})
+	return d
+}
+
 // SetDate of document
 func (d *Document) SetDate(date string) *Document {
 	d.Date = date
 	return d
 }
 
+// SetDateTime of document
+func (d *Document) SetDateTime(date time.Time) *Document {
+	d.DateTime = date
+	return d
+}
+
+// SetValidityDate of document
+func (d *Document) SetValidityDate(date string) *Document {
+	d.ValidityDate = date
+	return d
+}
+
 // SetPaymentTerm of document
 func (d *Document) SetPaymentTerm(term string) *Document {
 	d.PaymentTerm = term
 	return d
 }
 
+// SetDueDate of document
+func (d *Document) SetDueDate(date string) *Document {
+	d.DueDate = date
+	return d
+}
+
+// SetDueDateTime of document
+func (d *Document) SetDueDateTime(date time.Time) *Document {
+	d.DueDateTime = date
+	return d
+}
+
+// SetShipping of document
+func (d *Document) SetShipping(shipping *Shipping) *Document {
+	d.Shipping = shipping
+	return d
+}
+
+// SetSignature of document
+func (d *Document) SetSignature(signature *Signature) *Document {
+	d.Signature = signature
+	return d
+}
+
 // SetDefaultTax of document
 func (d *Document) SetDefaultTax(tax *Tax) *Document {
 	d.DefaultTax = tax
@@ -83,3 +170,43 @@ func (d *Document) SetDiscount(discount *Discount) *Document {
 	d.Discount = discount
 	return d
 }
+
+// SetDiscountPercent set document discount as percent of items total
+func (d *Document) SetDiscountPercent(percent decimal.Decimal) *Document {
+	return d.SetDiscount(&Discount{Percent: percent.String()})
+}
+
+// SetDiscountAmount set document discount as fixed amount
+func (d *Document) SetDiscountAmount(amount decimal.Decimal) *Document {
+	return d.SetDiscount(&Discount{Amount: amount.String()})
+}
+
+// SetWithholding of document
+func (d *Document) SetWithholding(withholding *Tax) *Document {
+	d.Withholding = withholding
+	return d
+}
+
+// SetLateFee of document
+func (d *Document) SetLateFee(lateFee *LateFee) *Document {
+	d.LateFee = lateFee
+	return d
+}
+
+// SetBankDetails of document
+func (d *Document) SetBankDetails(bankDetails *BankDetails) *Document {
+	d.BankDetails = bankDetails
+	return d
+}
+
+// AppendAttachment to document attachments
+func (d *Document) AppendAttachment(attachment *Attachment) *Document {
+	d.Attachments = append(d.Attachments, attachment)
+	return d
+}
+
+// AppendPayment to document, payments being subtracted from amount due
+func (d *Document) AppendPayment(payment *Payment) *Document {
+	d.Payments = append(d.Payments, payment)
+	return d
+}
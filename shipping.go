@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// Shipping define a shipping or handling charge, added to totals with its own tax
+type Shipping struct {
+	Label  string `json:"label,omitempty"` // Totals row title, default to Options.TextTotalShipping
+	Amount string `json:"amount,omitempty" validate:"required"`
+	Tax    *Tax   `json:"tax,omitempty"`
+}
+
+func (s *Shipping) amount() decimal.Decimal {
+	amount, _ := decimal.NewFromString(s.Amount)
+	return amount
+}
+
+// tax return shipping tax, extracted from amount when prices include tax
+func (s *Shipping) tax(included bool) decimal.Decimal {
+	if s.Tax == nil {
+		return decimal.NewFromFloat(0)
+	}
+
+	taxes := []*Tax{s.Tax}
+	if included {
+		return s.amount().Sub(netFromGross(taxes, s.amount()))
+	}
+
+	return totalTaxes(taxes, s.amount())
+}
+
+// addShipping add document shipping and its tax to totals
+func (d *Document) addShipping(totals *documentTotals) *documentTotals {
+	if d.Shipping == nil {
+		return totals
+	}
+
+	totals.shipping = d.Shipping.amount()
+	totals.shippingTax = d.Options.roundLine(d.Shipping.tax(d.Options.PricesIncludeTax))
+	totals.totalTax = totals.totalTax.Add(totals.shippingTax)
+
+	if d.Options.PricesIncludeTax {
+		totals.totalWithTax = totals.totalWithTax.Add(totals.shipping)
+		totals.totalWithDiscount = totals.totalWithDiscount.Add(totals.shipping.Sub(totals.shippingTax))
+	} else {
+		totals.totalWithTax = totals.totalWithTax.Add(totals.shipping).Add(totals.shippingTax)
+	}
+
+	return totals
+}
@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Signature define a signature block drawn below payment term. Without image,
+// line and caption are drawn so document can be signed by hand.
+type Signature struct {
+	Image []byte `json:"image,omitempty"` // PNG or JPEG signature image
+	Name  string `json:"name,omitempty"`  // Signer name
+	Date  string `json:"date,omitempty"`  // Signature date
+}
+
+// Signature block dimensions in mm
+const (
+	signatureWidth       float64 = 60
+	signatureImageHeight float64 = 20
+)
+
+// caption return signer name and date separated by a dash
+func (s *Signature) caption() string {
+	if len(s.Name) > 0 && len(s.Date) > 0 {
+		return fmt.Sprintf("%s - %s", s.Name, s.Date)
+	}
+
+	return s.Name + s.Date
+}
+
+// logo return signature image as a logo fitting signature box
+func (s *Signature) logo() *Logo {
+	_, format, err := image.DecodeConfig(bytes.NewReader(s.Image))
+	if err != nil {
+		return nil
+	}
+
+	return &Logo{
+		Bytes:     s.Image,
+		MimeType:  "image/" + format,
+		MaxWidth:  signatureWidth,
+		MaxHeight: signatureImageHeight,
+	}
+}
+
+func (d *Document) appendSignature(pdf *gofpdf.Fpdf) {
+	if d.Signature == nil {
+		return
+	}
+
+	// Check page height (image, line and caption)
+	y := pdf.GetY() + 10
+	if y+signatureImageHeight+7 > d.maxPageHeight() {
+		pdf.AddPage()
+		y = pdf.GetY()
+	}
+
+	x := d.endX(signatureWidth)
+
+	// Image
+	if len(d.Signature.Image) > 0 {
+		if logo := d.Signature.logo(); logo != nil {
+			logo.appendTo("signature", x, y, pdf)
+		}
+	}
+
+	// Line
+	lineY := y + signatureImageHeight + 1
+	pdf.SetDrawColor(d.Options.Theme.BaseTextColor[0], d.Options.Theme.BaseTextColor[1], d.Options.Theme.BaseTextColor[2])
+	pdf.Line(x, lineY, x+signatureWidth, lineY)
+	pdf.SetDrawColor(0, 0, 0)
+
+	// Caption
+	pdf.SetXY(x, lineY+1)
+	pdf.SetFont(d.Options.fontFamily(), "", SmallTextFontSize)
+	pdf.CellFormat(signatureWidth, 4, d.Options.encodeString(d.Signature.caption()), "0", 0, "C", false, 0, "")
+
+	pdf.SetFont(d.Options.fontFamily(), "", BaseTextFontSize)
+	pdf.SetY(lineY + 5)
+}
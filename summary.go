@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/shopspring/decimal"
+)
+
+// summarizeItems return true if items must be rendered as a summary
+func (d *Document) summarizeItems() bool {
+	return d.Options.SummaryItemsThreshold > 0 && len(d.activeItems()) > d.Options.SummaryItemsThreshold
+}
+
+// summaryItems group document items by taxes, each group being rendered as a single line
+// with items totals (items discounts included), converted to document currency
+func (d *Document) summaryItems() []*Item {
+	var keys []string
+	counts := map[string]int{}
+	totals := map[string]decimal.Decimal{}
+	taxes := map[string][]*Tax{}
+
+	for _, item := range d.convertedItems(d.activeItems()) {
+		itemTaxes := d.itemTaxes(item)
+
+		key := "none"
+		if len(itemTaxes) > 0 {
+			taxKeys := make([]string, 0, len(itemTaxes))
+			for _, tax := range itemTaxes {
+				taxKeys = append(taxKeys, tax.key())
+			}
+			key = strings.Join(taxKeys, "+")
+		}
+
+		if _, ok := totals[key]; !ok {
+			keys = append(keys, key)
+			totals[key] = decimal.NewFromFloat(0)
+			if len(itemTaxes) > 0 {
+				taxes[key] = make([]*Tax, len(itemTaxes))
+			}
+		}
+
+		counts[key]++
+		totals[key] = totals[key].Add(item.totalWithoutTaxAndWithDiscount())
+
+		// Fixed amount taxes are summed, percent taxes are kept as is
+		for index, tax := range itemTaxes {
+			taxType, taxAmount := tax.getTax()
+			if taxType == "amount" {
+				previous := decimal.NewFromFloat(0)
+				if taxes[key][index] != nil {
+					previous, _ = decimal.NewFromString(taxes[key][index].Amount)
+				}
+				taxes[key][index] = &Tax{Amount: previous.Add(taxAmount).String(), Compound: tax.Compound}
+			} else {
+				taxes[key][index] = tax
+			}
+		}
+	}
+
+	items := make([]*Item, 0, len(keys))
+	for _, key := range keys {
+		items = append(items, &Item{
+			Name:     fmt.Sprintf("%s (%d)", d.Options.TextItemsSummaryTitle, counts[key]),
+			UnitCost: totals[key].String(),
+			Quantity: "1",
+			Taxes:    taxes[key],
+		})
+	}
+
+	return items
+}
+
+func (d *Document) appendItemsSummaryNote(pdf *gofpdf.Fpdf) {
+	if len(d.Options.TextItemsSummaryNote) == 0 {
+		return
+	}
+
+	pdf.SetX(d.Options.MarginLeft)
+	pdf.SetFont(d.Options.fontFamily(), "", SmallTextFontSize)
+	pdf.SetTextColor(d.Options.Theme.GreyTextColor[0], d.Options.Theme.GreyTextColor[1], d.Options.Theme.GreyTextColor[2])
+	pdf.MultiCell(d.contentWidth(), 3, d.Options.encodeString(d.Options.TextItemsSummaryNote), "0", "L", false)
+
+	// Reset font
+	pdf.SetFont(d.Options.fontFamily(), "", BaseTextFontSize)
+	pdf.SetTextColor(d.Options.Theme.BaseTextColor[0], d.Options.Theme.BaseTextColor[1], d.Options.Theme.BaseTextColor[2])
+}
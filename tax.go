@@ -1,29 +1,151 @@
 package generator
 
 import (
+	"fmt"
+
 	"github.com/shopspring/decimal"
 )
 
 // Tax define tax as percent or fixed amount
 type Tax struct {
-	Percent string `json:"percent,omitempty"` // Tax in percent ex 17
-	Amount  string `json:"amount,omitempty"`  // Tax in amount ex 123.40
+	Percent  string `json:"percent,omitempty"`  // Tax in percent ex 17
+	Amount   string `json:"amount,omitempty"`   // Tax in amount ex 123.40
+	Compound bool   `json:"compound,omitempty"` // Compound tax is computed on total including previous taxes
+}
+
+// parse return tax type and value as set, amount taking precedence over
+// percent, and an error when value is not a number
+func (t *Tax) parse() (string, decimal.Decimal, error) {
+	return parseRate(t.Percent, t.Amount)
 }
 
+// getTax return tax type, percent when unset, and its value. Percents are
+// clamped between 0 and 100, documents rejecting them unless Options.ClampPercents.
 func (t *Tax) getTax() (string, decimal.Decimal) {
-	tax := "0"
-	taxType := "percent"
+	taxType, taxAmount, _ := t.parse()
+	if taxType == "percent" {
+		taxAmount = clampPercent(taxAmount)
+	}
+
+	return taxType, taxAmount
+}
+
+// percentOutOfRange return true if tax is a percent lower than 0 or greater than 100
+func (t *Tax) percentOutOfRange() bool {
+	taxType, taxAmount, _ := t.parse()
+	return taxType == "percent" && !taxAmount.Equal(clampPercent(taxAmount))
+}
+
+// Validate check tax value is a number, and a percent between 0 and 100
+func (t *Tax) Validate() error {
+	return t.validate(true)
+}
+
+// validate check tax value is a number, out of range percents being accepted
+// when not strict
+func (t *Tax) validate(strict bool) error {
+	if _, _, err := t.parse(); err != nil {
+		return ErrInvalidNumber
+	}
+
+	if strict && t.percentOutOfRange() {
+		return ErrPercentOutOfRange
+	}
+
+	return nil
+}
+
+// parseRate return rate type, one of percent or amount, and value. Amount
+// take precedence over percent, unset rates being a zero percent.
+func parseRate(percent string, amount string) (string, decimal.Decimal, error) {
+	if len(amount) > 0 {
+		value, err := decimal.NewFromString(amount)
+		return "amount", value, err
+	}
+
+	if len(percent) > 0 {
+		value, err := decimal.NewFromString(percent)
+		return "percent", value, err
+	}
+
+	return "percent", decimal.NewFromFloat(0), nil
+}
+
+// clampPercent return percent clamped between 0 and 100
+func clampPercent(percent decimal.Decimal) decimal.Decimal {
+	if percent.IsNegative() {
+		return decimal.NewFromFloat(0)
+	}
 
-	if len(t.Percent) > 0 {
-		tax = t.Percent
+	if percent.GreaterThan(decimal.NewFromFloat(100)) {
+		return decimal.NewFromFloat(100)
 	}
 
-	if len(t.Amount) > 0 {
-		tax = t.Amount
-		taxType = "amount"
+	return percent
+}
+
+// key return tax identifier, used to group identical taxes
+func (t *Tax) key() string {
+	taxType, taxAmount := t.getTax()
+	key := fmt.Sprintf("%s:%s", taxType, taxAmount.String())
+	if t.Compound {
+		key = "compound:" + key
 	}
 
-	decVal, _ := decimal.NewFromString(tax)
+	return key
+}
+
+// taxLine hold a computed tax amount with the base it was computed on
+type taxLine struct {
+	tax    *Tax
+	base   decimal.Decimal
+	amount decimal.Decimal
+}
+
+// computeTaxes compute taxes in order from net amount, compound taxes being
+// computed on net amount plus previous taxes
+func computeTaxes(taxes []*Tax, net decimal.Decimal) []taxLine {
+	hundred := decimal.NewFromFloat(100)
+	running := net
+
+	lines := make([]taxLine, 0, len(taxes))
+	for _, tax := range taxes {
+		base := net
+		if tax.Compound {
+			base = running
+		}
+
+		taxType, taxAmount := tax.getTax()
+		amount := taxAmount
+		if taxType != "amount" {
+			amount = base.Mul(taxAmount).Div(hundred)
+		}
+
+		lines = append(lines, taxLine{tax: tax, base: base, amount: amount})
+		running = running.Add(amount)
+	}
+
+	return lines
+}
+
+// totalTaxes return the sum of taxes computed from net amount
+func totalTaxes(taxes []*Tax, net decimal.Decimal) decimal.Decimal {
+	total := decimal.NewFromFloat(0)
+	for _, line := range computeTaxes(taxes, net) {
+		total = total.Add(line.amount)
+	}
+
+	return total
+}
+
+// netFromGross extract net amount from gross amount including taxes.
+// Gross being an affine function of net, it is inverted from gross of 0 and 1.
+func netFromGross(taxes []*Tax, gross decimal.Decimal) decimal.Decimal {
+	zero := decimal.NewFromFloat(0)
+	one := decimal.NewFromFloat(1)
+
+	fixed := totalTaxes(taxes, zero)
+	factor := one.Add(totalTaxes(taxes, one)).Sub(fixed)
 
-	return taxType, decVal
+	return gross.Sub(fixed).Div(factor)
 }
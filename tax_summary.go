@@ -0,0 +1,156 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/shopspring/decimal"
+)
+
+// taxSummaryLine hold totals of items sharing the same tax rate
+type taxSummaryLine struct {
+	label string
+	rate  *Tax // First tax of line, nil for untaxed amounts
+	net   decimal.Decimal
+	tax   decimal.Decimal
+	gross decimal.Decimal
+}
+
+// taxSummaryLines group items and shipping totals by tax rate, document discount
+// included. Percent taxes are grouped by rate, fixed amount taxes in a single line.
+func (d *Document) taxSummaryLines() []*taxSummaryLine {
+	totals := d.computeTotals()
+
+	// Share of items totals remaining after document discount
+	ratio := decimal.NewFromFloat(1)
+	if d.Discount != nil {
+		if d.Options.PricesIncludeTax && !totals.totalGross.IsZero() {
+			ratio = totals.totalWithTax.Sub(totals.shipping).Div(totals.totalGross)
+		} else if !d.Options.PricesIncludeTax && !totals.total.IsZero() {
+			ratio = totals.totalWithDiscount.Div(totals.total)
+		}
+	}
+
+	// Taxes computed on each amount, untaxed amounts having a nil tax
+	var entries []taxLine
+	addEntries := func(taxes []*Tax, total decimal.Decimal) {
+		if len(taxes) == 0 {
+			entries = append(entries, taxLine{base: total, amount: decimal.NewFromFloat(0)})
+			return
+		}
+
+		net := total
+		if d.Options.PricesIncludeTax {
+			net = netFromGross(taxes, total)
+		}
+		entries = append(entries, computeTaxes(taxes, net)...)
+	}
+
+	for _, item := range d.convertedItems(d.activeItems()) {
+		addEntries(d.itemTaxes(item), item.totalWithoutTaxAndWithDiscount().Mul(ratio))
+	}
+
+	// Shipping is not discounted
+	if d.Shipping != nil {
+		var taxes []*Tax
+		if d.Shipping.Tax != nil {
+			taxes = []*Tax{d.Shipping.Tax}
+		}
+		addEntries(taxes, d.Shipping.amount())
+	}
+
+	var keys []string
+	lines := map[string]*taxSummaryLine{}
+
+	// Compound taxes base include previous taxes, they are summarized apart
+	for _, entry := range entries {
+		key := "none"
+		label := "--"
+		if entry.tax != nil {
+			taxType, taxAmount := entry.tax.getTax()
+			if taxType == "amount" {
+				key = "amount"
+				label = d.Options.TextTaxSummaryFixedTitle
+			} else {
+				key = entry.tax.key()
+				label = fmt.Sprintf("%s %%", taxAmount.String())
+				if entry.tax.Compound {
+					label = fmt.Sprintf("%s %% (%s)", taxAmount.String(), d.Options.TextTaxSummaryCompoundTitle)
+				}
+			}
+		}
+
+		line, ok := lines[key]
+		if !ok {
+			keys = append(keys, key)
+			line = &taxSummaryLine{
+				label: label,
+				rate:  entry.tax,
+				net:   decimal.NewFromFloat(0),
+				tax:   decimal.NewFromFloat(0),
+				gross: decimal.NewFromFloat(0),
+			}
+			lines[key] = line
+		}
+
+		line.net = line.net.Add(entry.base)
+		line.tax = line.tax.Add(entry.amount)
+		line.gross = line.gross.Add(entry.base.Add(entry.amount))
+	}
+
+	result := make([]*taxSummaryLine, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, lines[key])
+	}
+
+	return result
+}
+
+// taxSummaryHeight return height of tax summary table, zero when not rendered
+func (d *Document) taxSummaryHeight() float64 {
+	lines := d.taxSummaryLines()
+	if len(lines) < 2 {
+		return 0
+	}
+
+	return 10 + 5*float64(len(lines)+1)
+}
+
+// appendTaxSummary draw net, tax and gross amounts per tax rate above totals,
+// only when items use more than one distinct rate
+func (d *Document) appendTaxSummary(pdf *gofpdf.Fpdf) {
+	lines := d.taxSummaryLines()
+	if len(lines) < 2 {
+		return
+	}
+
+	ac := d.Options.moneyFormatter()
+	x := d.endX(80)
+	y := pdf.GetY() + 10
+
+	// Titles
+	pdf.SetXY(x, y)
+	pdf.SetFont(d.Options.fontFamily(), "B", SmallTextFontSize)
+	pdf.SetFillColor(d.Options.Theme.DarkBgColor[0], d.Options.Theme.DarkBgColor[1], d.Options.Theme.DarkBgColor[2])
+	pdf.Rect(x, y, 80, 5, "F")
+	pdf.CellFormat(20, 5, d.Options.encodeString(d.Options.TextTaxSummaryRateTitle), "0", 0, "L", false, 0, "")
+	pdf.CellFormat(20, 5, d.Options.encodeString(d.Options.TextTaxSummaryNetTitle), "0", 0, "R", false, 0, "")
+	pdf.CellFormat(20, 5, d.Options.encodeString(d.Options.TextTaxSummaryTaxTitle), "0", 0, "R", false, 0, "")
+	pdf.CellFormat(20, 5, d.Options.encodeString(d.Options.TextTaxSummaryGrossTitle), "0", 0, "R", false, 0, "")
+
+	// Lines
+	pdf.SetFont(d.Options.fontFamily(), "", SmallTextFontSize)
+	pdf.SetFillColor(d.Options.Theme.GreyBgColor[0], d.Options.Theme.GreyBgColor[1], d.Options.Theme.GreyBgColor[2])
+	for i, line := range lines {
+		lineY := y + 5*float64(i+1)
+		pdf.SetXY(x, lineY)
+		pdf.Rect(x, lineY, 80, 5, "F")
+		pdf.CellFormat(20, 5, d.Options.encodeString(line.label), "0", 0, "L", false, 0, "")
+		pdf.CellFormat(20, 5, ac.FormatMoneyDecimal(line.net), "0", 0, "R", false, 0, "")
+		pdf.CellFormat(20, 5, ac.FormatMoneyDecimal(line.tax), "0", 0, "R", false, 0, "")
+		pdf.CellFormat(20, 5, ac.FormatMoneyDecimal(line.gross), "0", 0, "R", false, 0, "")
+	}
+
+	pdf.SetFont(d.Options.fontFamily(), "", BaseTextFontSize)
+	pdf.SetY(y + 5*float64(len(lines)+1))
+}
@@ -0,0 +1,27 @@
+package generator
+
+// Theme define document colors as RGB, unset (black) colors default to
+// BaseTextColor, GreyTextColor, GreyBgColor, DarkBgColor and AltRowBgColor
+type Theme struct {
+	BaseTextColor [3]int `json:"base_text_color,omitempty"`  // Base text color
+	GreyTextColor [3]int `json:"grey_text_color,omitempty"`  // Secondary text color (descriptions, notes)
+	GreyBgColor   [3]int `json:"grey_bg_color,omitempty"`    // Light background color (values, table titles)
+	DarkBgColor   [3]int `json:"dark_bg_color,omitempty"`    // Dark background color (title, totals titles)
+	AltRowBgColor [3]int `json:"alt_row_bg_color,omitempty"` // Alternate items rows background color
+}
+
+// SetDefaults set unset theme colors from package default colors,
+// called by defaults.Set on options
+func (t *Theme) SetDefaults() {
+	setDefaultColor(&t.BaseTextColor, BaseTextColor)
+	setDefaultColor(&t.GreyTextColor, GreyTextColor)
+	setDefaultColor(&t.GreyBgColor, GreyBgColor)
+	setDefaultColor(&t.DarkBgColor, DarkBgColor)
+	setDefaultColor(&t.AltRowBgColor, AltRowBgColor)
+}
+
+func setDefaultColor(color *[3]int, value []int) {
+	if *color == [3]int{} && len(value) == 3 {
+		copy(color[:], value)
+	}
+}
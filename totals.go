@@ -0,0 +1,219 @@
+package generator
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// Totals define document totals, as displayed in totals block
+type Totals struct {
+	TotalNet      decimal.Decimal `json:"total_net"`      // Total without tax, with items discounts
+	TotalDiscount decimal.Decimal `json:"total_discount"` // Document discount amount
+	TotalShipping decimal.Decimal `json:"total_shipping"` // Shipping amount without tax
+	TotalTax      decimal.Decimal `json:"total_tax"`      // Total tax, including shipping tax
+	TotalGross    decimal.Decimal `json:"total_gross"`    // Final total with tax
+	Withholding   decimal.Decimal `json:"withholding"`    // Withholding tax amount
+	Prepaid       decimal.Decimal `json:"prepaid"`        // Payments sum
+	LateFee       decimal.Decimal `json:"late_fee"`       // Interest on overdue amount
+	AmountDue     decimal.Decimal `json:"amount_due"`     // Total gross minus withholding and payments, plus late fee
+}
+
+// ComputeTotals compute document totals without generating a pdf. Document
+// options are left untouched, defaults being applied to a copy.
+func (d *Document) ComputeTotals() (Totals, error) {
+	options, err := d.Options.withDefaults()
+	if err != nil {
+		return Totals{}, err
+	}
+
+	doc := *d
+	doc.Options = options
+
+	if err := doc.validateItems(); err != nil {
+		return Totals{}, err
+	}
+
+	totals := doc.computeTotals()
+
+	discount := decimal.NewFromFloat(0)
+	if d.Discount != nil {
+		discount = totals.total.Sub(totals.totalWithDiscount)
+	}
+
+	return Totals{
+		TotalNet:      totals.total,
+		TotalDiscount: discount,
+		TotalShipping: totals.shipping,
+		TotalTax:      totals.totalTax,
+		TotalGross:    totals.totalWithTax,
+		Withholding:   totals.withholding,
+		Prepaid:       totals.prepaid,
+		LateFee:       totals.lateFee,
+		AmountDue:     totals.amountDue,
+	}, nil
+}
+
+// documentTotals hold the computed totals of a document
+type documentTotals struct {
+	total             decimal.Decimal // Total without tax, with items discounts
+	totalWithDiscount decimal.Decimal // Total without tax, with document discount
+	totalTax          decimal.Decimal // Total tax
+	totalWithTax      decimal.Decimal // Final total
+	totalGross        decimal.Decimal // Total with tax, before document discount
+	shipping          decimal.Decimal // Shipping amount
+	shippingTax       decimal.Decimal // Shipping tax, included in total tax
+	withholding       decimal.Decimal // Withholding tax
+	prepaid           decimal.Decimal // Payments sum
+	lateFee           decimal.Decimal // Interest on overdue amount
+	amountDue         decimal.Decimal // Final total minus withholding tax and payments, plus late fee
+}
+
+// computeTotals compute document totals from items, taxes and discounts
+func (d *Document) computeTotals() *documentTotals {
+	items := d.convertedItems(d.activeItems())
+
+	if d.Options.PricesIncludeTax {
+		return d.addLateFee(d.addPayments(d.addWithholding(d.roundTotals(d.addShipping(d.computeTotalsTaxIncluded(items))))))
+	}
+
+	// Get total (without tax)
+	total, _ := decimal.NewFromString("0")
+
+	for _, item := range items {
+		total = total.Add(d.Options.roundLine(item.totalWithoutTaxAndWithDiscount()))
+	}
+
+	// Apply document discount, clamped to total
+	totalWithDiscount := decimal.NewFromFloat(0)
+	if d.Discount != nil {
+		totalWithDiscount = total.Sub(d.Discount.amountOf(total))
+	}
+
+	// Tax
+	totalTax := decimal.NewFromFloat(0)
+	if d.Discount == nil {
+		for _, item := range items {
+			totalTax = totalTax.Add(d.Options.roundLine(item.taxWithDiscount(false)))
+		}
+	} else {
+		discountPercent := d.Discount.percentOf(total)
+
+		for _, item := range items {
+			// Remove doc discount % from item total without tax and item discount
+			itemTotal := d.Options.roundLine(item.totalWithoutTaxAndWithDiscount())
+			toSub := discountPercent.Mul(itemTotal).Div(decimal.NewFromFloat(100))
+			itemTotalDiscounted := itemTotal.Sub(toSub)
+
+			// Then recompute taxes on itemTotalDiscounted, fixed amount taxes being kept as is
+			totalTax = totalTax.Add(d.Options.roundLine(totalTaxes(item.taxes(), itemTotalDiscounted)))
+		}
+	}
+
+	// finalTotal
+	totalWithTax := total.Add(totalTax)
+	if d.Discount != nil {
+		totalWithTax = totalWithDiscount.Add(totalTax)
+	}
+
+	// Gross total
+	totalGross := total
+	for _, item := range items {
+		totalGross = totalGross.Add(d.Options.roundLine(item.taxWithDiscount(false)))
+	}
+
+	return d.addLateFee(d.addPayments(d.addWithholding(d.roundTotals(d.addShipping(&documentTotals{
+		total:             total,
+		totalWithDiscount: totalWithDiscount,
+		totalTax:          totalTax,
+		totalWithTax:      totalWithTax,
+		totalGross:        totalGross,
+	})))))
+}
+
+// computeTotalsTaxIncluded compute document totals when items prices include tax,
+// tax being extracted from prices instead of added on top
+func (d *Document) computeTotalsTaxIncluded(items []*Item) *documentTotals {
+	hundred := decimal.NewFromFloat(100)
+
+	// Get gross total
+	totalGross := decimal.NewFromFloat(0)
+	for _, item := range items {
+		totalGross = totalGross.Add(d.Options.roundLine(item.totalWithoutTaxAndWithDiscount()))
+	}
+
+	// Document discount as percent of gross total
+	discountPercent := decimal.NewFromFloat(0)
+	if d.Discount != nil {
+		discountPercent = d.Discount.percentOf(totalGross)
+	}
+
+	// Extract tax from items gross totals
+	totalTaxUndiscounted := decimal.NewFromFloat(0)
+	totalTax := decimal.NewFromFloat(0)
+	for _, item := range items {
+		taxes := item.taxes()
+		if len(taxes) == 0 {
+			continue
+		}
+
+		itemGross := d.Options.roundLine(item.totalWithoutTaxAndWithDiscount())
+		itemGrossDiscounted := itemGross.Sub(itemGross.Mul(discountPercent).Div(hundred))
+
+		totalTaxUndiscounted = totalTaxUndiscounted.Add(d.Options.roundLine(itemGross.Sub(netFromGross(taxes, itemGross))))
+		totalTax = totalTax.Add(d.Options.roundLine(itemGrossDiscounted.Sub(netFromGross(taxes, itemGrossDiscounted))))
+	}
+
+	totalWithTax := totalGross.Sub(totalGross.Mul(discountPercent).Div(hundred))
+
+	return &documentTotals{
+		total:             totalGross.Sub(totalTaxUndiscounted),
+		totalWithDiscount: totalWithTax.Sub(totalTax),
+		totalTax:          totalTax,
+		totalWithTax:      totalWithTax,
+		totalGross:        totalGross,
+	}
+}
+
+// roundTotals round totals according to rounding mode, totals with tax being
+// recomputed from rounded amounts so displayed totals add up
+func (d *Document) roundTotals(totals *documentTotals) *documentTotals {
+	if d.Options.RoundingMode == RoundingModeNone || len(d.Options.RoundingMode) == 0 {
+		return totals
+	}
+
+	totals.total = d.Options.round(totals.total)
+	totals.totalWithDiscount = d.Options.round(totals.totalWithDiscount)
+	totals.totalTax = d.Options.round(totals.totalTax)
+	totals.totalGross = d.Options.round(totals.totalGross)
+	totals.shipping = d.Options.round(totals.shipping)
+
+	if d.Options.PricesIncludeTax {
+		totals.totalWithTax = d.Options.round(totals.totalWithTax)
+		totals.totalWithDiscount = totals.totalWithTax.Sub(totals.totalTax)
+	} else if d.Discount != nil {
+		totals.totalWithTax = totals.totalWithDiscount.Add(totals.shipping).Add(totals.totalTax)
+	} else {
+		totals.totalWithTax = totals.total.Add(totals.shipping).Add(totals.totalTax)
+	}
+
+	return totals
+}
+
+// addWithholding compute withholding tax on net or gross total, and amount due
+func (d *Document) addWithholding(totals *documentTotals) *documentTotals {
+	totals.withholding = decimal.NewFromFloat(0)
+	totals.amountDue = totals.totalWithTax
+
+	if d.Withholding == nil {
+		return totals
+	}
+
+	base := totals.totalWithTax.Sub(totals.totalTax)
+	if d.Options.WithholdingBase == WithholdingBaseGross {
+		base = totals.totalWithTax
+	}
+
+	totals.withholding = d.Options.round(totalTaxes([]*Tax{d.Withholding}, base))
+	totals.amountDue = totals.totalWithTax.Sub(totals.withholding)
+
+	return totals
+}
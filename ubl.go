@@ -0,0 +1,353 @@
+package generator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// UBL 2.1 namespaces
+const (
+	ublInvoiceNamespace = "urn:oasis:names:specification:ubl:schema:xsd:Invoice-2"
+	ublCACNamespace     = "urn:oasis:names:specification:ubl:schema:xsd:CommonAggregateComponents-2"
+	ublCBCNamespace     = "urn:oasis:names:specification:ubl:schema:xsd:CommonBasicComponents-2"
+)
+
+// ublInvoice define the UBL 2.1 Invoice root, elements being in schema order
+type ublInvoice struct {
+	XMLName                 xml.Name              `xml:"Invoice"`
+	Xmlns                   string                `xml:"xmlns,attr"`
+	XmlnsCAC                string                `xml:"xmlns:cac,attr"`
+	XmlnsCBC                string                `xml:"xmlns:cbc,attr"`
+	UBLVersionID            string                `xml:"cbc:UBLVersionID"`
+	ID                      string                `xml:"cbc:ID"`
+	IssueDate               string                `xml:"cbc:IssueDate"`
+	DueDate                 string                `xml:"cbc:DueDate,omitempty"`
+	InvoiceTypeCode         string                `xml:"cbc:InvoiceTypeCode"`
+	Note                    string                `xml:"cbc:Note,omitempty"`
+	DocumentCurrencyCode    string                `xml:"cbc:DocumentCurrencyCode"`
+	AccountingSupplierParty ublParty              `xml:"cac:AccountingSupplierParty"`
+	AccountingCustomerParty ublParty              `xml:"cac:AccountingCustomerParty"`
+	AllowanceCharges        []ublAllowanceCharge  `xml:"cac:AllowanceCharge"`
+	TaxTotal                ublTaxTotal           `xml:"cac:TaxTotal"`
+	LegalMonetaryTotal      ublLegalMonetaryTotal `xml:"cac:LegalMonetaryTotal"`
+	InvoiceLines            []ublInvoiceLine      `xml:"cac:InvoiceLine"`
+}
+
+type ublAmount struct {
+	CurrencyID string `xml:"currencyID,attr"`
+	Value      string `xml:",chardata"`
+}
+
+type ublQuantity struct {
+	UnitCode string `xml:"unitCode,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type ublParty struct {
+	Name           string             `xml:"cac:Party>cac:PartyName>cbc:Name"`
+	PostalAddress  *ublPostalAddress  `xml:"cac:Party>cac:PostalAddress,omitempty"`
+	PartyTaxScheme *ublPartyTaxScheme `xml:"cac:Party>cac:PartyTaxScheme,omitempty"`
+}
+
+type ublPartyTaxScheme struct {
+	CompanyID string `xml:"cbc:CompanyID"`
+	TaxScheme string `xml:"cac:TaxScheme>cbc:ID"`
+}
+
+type ublPostalAddress struct {
+	StreetName           string      `xml:"cbc:StreetName,omitempty"`
+	AdditionalStreetName string      `xml:"cbc:AdditionalStreetName,omitempty"`
+	CityName             string      `xml:"cbc:CityName,omitempty"`
+	PostalZone           string      `xml:"cbc:PostalZone,omitempty"`
+	Country              *ublCountry `xml:"cac:Country,omitempty"`
+}
+
+type ublCountry struct {
+	IdentificationCode string `xml:"cbc:IdentificationCode"`
+}
+
+type ublAllowanceCharge struct {
+	ChargeIndicator       bool            `xml:"cbc:ChargeIndicator"`
+	AllowanceChargeReason string          `xml:"cbc:AllowanceChargeReason,omitempty"`
+	Amount                ublAmount       `xml:"cbc:Amount"`
+	TaxCategory           *ublTaxCategory `xml:"cac:TaxCategory,omitempty"`
+}
+
+type ublTaxTotal struct {
+	TaxAmount    ublAmount        `xml:"cbc:TaxAmount"`
+	TaxSubtotals []ublTaxSubtotal `xml:"cac:TaxSubtotal"`
+}
+
+type ublTaxSubtotal struct {
+	TaxableAmount ublAmount      `xml:"cbc:TaxableAmount"`
+	TaxAmount     ublAmount      `xml:"cbc:TaxAmount"`
+	TaxCategory   ublTaxCategory `xml:"cac:TaxCategory"`
+}
+
+type ublTaxCategory struct {
+	ID        string `xml:"cbc:ID"`
+	Percent   string `xml:"cbc:Percent,omitempty"`
+	TaxScheme string `xml:"cac:TaxScheme>cbc:ID"`
+}
+
+type ublLegalMonetaryTotal struct {
+	LineExtensionAmount  ublAmount  `xml:"cbc:LineExtensionAmount"`
+	TaxExclusiveAmount   ublAmount  `xml:"cbc:TaxExclusiveAmount"`
+	TaxInclusiveAmount   ublAmount  `xml:"cbc:TaxInclusiveAmount"`
+	AllowanceTotalAmount ublAmount  `xml:"cbc:AllowanceTotalAmount"`
+	ChargeTotalAmount    ublAmount  `xml:"cbc:ChargeTotalAmount"`
+	PrepaidAmount        *ublAmount `xml:"cbc:PrepaidAmount,omitempty"`
+	PayableAmount        ublAmount  `xml:"cbc:PayableAmount"`
+}
+
+type ublInvoiceLine struct {
+	ID                    string               `xml:"cbc:ID"`
+	InvoicedQuantity      ublQuantity          `xml:"cbc:InvoicedQuantity"`
+	LineExtensionAmount   ublAmount            `xml:"cbc:LineExtensionAmount"`
+	AllowanceCharges      []ublAllowanceCharge `xml:"cac:AllowanceCharge"`
+	ItemDescription       string               `xml:"cac:Item>cbc:Description,omitempty"`
+	ItemName              string               `xml:"cac:Item>cbc:Name"`
+	ClassifiedTaxCategory ublTaxCategory       `xml:"cac:Item>cac:ClassifiedTaxCategory"`
+	PriceAmount           ublAmount            `xml:"cac:Price>cbc:PriceAmount"`
+}
+
+// vatCategory return the EN 16931 VAT category code and percent of tax:
+// standard rate, zero rated or outside scope when there is no tax
+func vatCategory(tax *Tax) (string, string) {
+	if tax == nil {
+		return "O", ""
+	}
+
+	taxType, taxAmount := tax.getTax()
+	if taxType == "amount" {
+		return "S", ""
+	}
+
+	if taxAmount.IsZero() {
+		return "Z", "0"
+	}
+
+	return "S", taxAmount.String()
+}
+
+// newUBLTaxCategory return UBL tax category of first tax
+func newUBLTaxCategory(tax *Tax) ublTaxCategory {
+	id, percent := vatCategory(tax)
+	return ublTaxCategory{ID: id, Percent: percent, TaxScheme: "VAT"}
+}
+
+// netLine return item unit price, amount before discount and total after
+// discount, all without tax
+func (d *Document) netLine(item *Item) (price, amount, total decimal.Decimal) {
+	taxes := d.itemTaxes(item)
+
+	price = item.unitCost()
+	amount = item.totalWithoutTax()
+	total = item.totalWithoutTaxAndWithDiscount()
+	if d.Options.PricesIncludeTax && len(taxes) > 0 {
+		price = netFromGross(taxes, price)
+		amount = netFromGross(taxes, amount)
+		total = netFromGross(taxes, total)
+	}
+
+	return price, amount, total
+}
+
+// exportCurrency return upper cased Options.CurrencyCode, required by
+// electronic invoices formats
+func (d *Document) exportCurrency() (string, error) {
+	if len(d.Options.CurrencyCode) == 0 {
+		return "", &FieldError{Field: "Options.CurrencyCode", Err: ErrMissingCurrencyCode}
+	}
+
+	return strings.ToUpper(d.Options.CurrencyCode), nil
+}
+
+// exportPrepaid return amounts subtracted from total with tax before amount
+// due, payments and withholding tax, so payable amount is total with tax
+// minus prepaid amount in electronic invoices
+func exportPrepaid(totals Totals) decimal.Decimal {
+	return totals.Prepaid.Add(totals.Withholding)
+}
+
+// exportPayable return amount due without late fee, interest not being part
+// of document total so payable amount stays total with tax minus prepaid amount
+func exportPayable(totals Totals) decimal.Decimal {
+	return totals.AmountDue.Sub(totals.LateFee)
+}
+
+// discountTax return tax of document discount in electronic invoices, the tax
+// of first item as discount is not split by tax rate
+func (d *Document) discountTax() *Tax {
+	items := d.activeItems()
+	if len(items) == 0 {
+		return nil
+	}
+
+	if taxes := d.itemTaxes(items[0]); len(taxes) > 0 {
+		return taxes[0]
+	}
+
+	return nil
+}
+
+func newUBLParty(contact *Contact) ublParty {
+	party := ublParty{Name: contact.Name}
+	if contact.Address != nil {
+		party.PostalAddress = &ublPostalAddress{
+			StreetName:           contact.Address.Address,
+			AdditionalStreetName: contact.Address.Address2,
+			CityName:             contact.Address.City,
+			PostalZone:           contact.Address.PostalCode,
+		}
+
+		if len(contact.Address.CountryCode) > 0 {
+			party.PostalAddress.Country = &ublCountry{IdentificationCode: strings.ToUpper(contact.Address.CountryCode)}
+		}
+	}
+
+	if len(contact.TaxID) > 0 {
+		party.PartyTaxScheme = &ublPartyTaxScheme{CompanyID: normalizeTaxID(contact.TaxID), TaxScheme: "VAT"}
+	}
+
+	return party
+}
+
+// ExportUBL serialize document as an OASIS UBL 2.1 Invoice xml, monetary totals
+// being computed by ComputeTotals
+func (d *Document) ExportUBL() ([]byte, error) {
+	totals, err := d.ComputeTotals()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	currency, err := d.exportCurrency()
+	if err != nil {
+		return nil, err
+	}
+
+	precision := int32(d.Options.CurrencyPrecision)
+	amount := func(value decimal.Decimal) ublAmount {
+		return ublAmount{CurrencyID: currency, Value: value.StringFixed(precision)}
+	}
+
+	typeCode := "380"
+	if d.Type == CreditNote {
+		typeCode = "381"
+	}
+
+	invoice := ublInvoice{
+		Xmlns:                   ublInvoiceNamespace,
+		XmlnsCAC:                ublCACNamespace,
+		XmlnsCBC:                ublCBCNamespace,
+		UBLVersionID:            "2.1",
+		ID:                      d.Ref,
+		IssueDate:               d.dateTime().Format("2006-01-02"),
+		InvoiceTypeCode:         typeCode,
+		Note:                    d.Description,
+		DocumentCurrencyCode:    currency,
+		AccountingSupplierParty: newUBLParty(d.Company),
+		AccountingCustomerParty: newUBLParty(d.Customer),
+	}
+
+	if dueDate, ok := d.dueDateTime(); ok {
+		invoice.DueDate = dueDate.Format("2006-01-02")
+	}
+
+	// Document discount and shipping
+	if d.Discount != nil {
+		taxCategory := newUBLTaxCategory(d.discountTax())
+		invoice.AllowanceCharges = append(invoice.AllowanceCharges, ublAllowanceCharge{
+			ChargeIndicator:       false,
+			AllowanceChargeReason: d.Discount.Label,
+			Amount:                amount(totals.TotalDiscount),
+			TaxCategory:           &taxCategory,
+		})
+	}
+
+	if d.Shipping != nil {
+		reason := d.Shipping.Label
+		if len(reason) == 0 {
+			reason = d.Options.TextTotalShipping
+		}
+
+		taxCategory := newUBLTaxCategory(d.Shipping.Tax)
+		invoice.AllowanceCharges = append(invoice.AllowanceCharges, ublAllowanceCharge{
+			ChargeIndicator:       true,
+			AllowanceChargeReason: reason,
+			Amount:                amount(totals.TotalShipping),
+			TaxCategory:           &taxCategory,
+		})
+	}
+
+	// Taxes by rate
+	invoice.TaxTotal.TaxAmount = amount(totals.TotalTax)
+	for _, line := range d.taxSummaryLines() {
+		invoice.TaxTotal.TaxSubtotals = append(invoice.TaxTotal.TaxSubtotals, ublTaxSubtotal{
+			TaxableAmount: amount(line.net),
+			TaxAmount:     amount(line.tax),
+			TaxCategory:   newUBLTaxCategory(line.rate),
+		})
+	}
+
+	// Totals
+	taxExclusive := totals.TotalGross.Sub(totals.TotalTax)
+	invoice.LegalMonetaryTotal = ublLegalMonetaryTotal{
+		LineExtensionAmount:  amount(taxExclusive.Add(totals.TotalDiscount).Sub(totals.TotalShipping)),
+		TaxExclusiveAmount:   amount(taxExclusive),
+		TaxInclusiveAmount:   amount(totals.TotalGross),
+		AllowanceTotalAmount: amount(totals.TotalDiscount),
+		ChargeTotalAmount:    amount(totals.TotalShipping),
+		PayableAmount:        amount(exportPayable(totals)),
+	}
+
+	if d.Withholding != nil || len(d.Payments) > 0 {
+		prepaid := amount(exportPrepaid(totals))
+		invoice.LegalMonetaryTotal.PrepaidAmount = &prepaid
+	}
+
+	// Lines, amounts without tax
+	for index, item := range d.convertedItems(d.activeItems()) {
+		taxes := d.itemTaxes(item)
+		price, lineAmount, lineTotal := d.netLine(item)
+
+		line := ublInvoiceLine{
+			ID:                  fmt.Sprintf("%d", index+1),
+			InvoicedQuantity:    ublQuantity{UnitCode: "C62", Value: item.quantity().String()},
+			LineExtensionAmount: amount(lineTotal),
+			ItemDescription:     item.Description,
+			ItemName:            item.Name,
+			PriceAmount:         amount(price),
+		}
+
+		if item.Discount != nil {
+			line.AllowanceCharges = append(line.AllowanceCharges, ublAllowanceCharge{
+				ChargeIndicator:       false,
+				AllowanceChargeReason: item.Discount.Label,
+				Amount:                amount(lineAmount.Sub(lineTotal)),
+			})
+		}
+
+		if len(taxes) > 0 {
+			line.ClassifiedTaxCategory = newUBLTaxCategory(taxes[0])
+		} else {
+			line.ClassifiedTaxCategory = newUBLTaxCategory(nil)
+		}
+
+		invoice.InvoiceLines = append(invoice.InvoiceLines, line)
+	}
+
+	output, err := xml.MarshalIndent(invoice, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), output...), nil
+}
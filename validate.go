@@ -1,11 +1,198 @@
 package generator
 
 import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
 	"gopkg.in/go-playground/validator.v9"
 )
 
 // Validate document fields
 func (d *Document) Validate() error {
 	validate := validator.New()
-	return validate.Struct(d)
+	if err := validate.Struct(d); err != nil {
+		return err
+	}
+
+	if len(d.Items) == 0 {
+		return ErrNoItems
+	}
+
+	if err := d.validateItems(); err != nil {
+		return err
+	}
+
+	if err := d.Options.validate(); err != nil {
+		return err
+	}
+
+	if d.Discount != nil {
+		if err := d.Discount.validate(d.strictPercents()); err != nil {
+			return &FieldError{Field: "Discount", Err: err}
+		}
+	}
+
+	if d.Discount != nil && d.Options != nil && d.Options.StrictDiscounts && d.Discount.exceeds(d.itemsTotal()) {
+		return &FieldError{Field: "Discount", Err: ErrDiscountExceedsTotal}
+	}
+
+	if d.DefaultTax != nil {
+		if err := d.DefaultTax.validate(d.strictPercents()); err != nil {
+			return &FieldError{Field: "DefaultTax", Err: err}
+		}
+	}
+
+	if err := d.validateTaxIDs(); err != nil {
+		return err
+	}
+
+	if err := d.validateItemColumns(); err != nil {
+		return err
+	}
+
+	if err := d.validateAttachments(); err != nil {
+		return err
+	}
+
+	if d.Withholding != nil {
+		if withholdingType, withholdingAmount, _ := d.Withholding.parse(); withholdingType == "percent" && withholdingAmount.GreaterThan(decimal.NewFromFloat(100)) {
+			return ErrWithholdingExceedsTotal
+		}
+	}
+
+	for index, payment := range d.Payments {
+		if _, err := decimal.NewFromString(payment.Amount); err != nil {
+			return &FieldError{Field: fmt.Sprintf("Payments[%d].Amount", index), Err: ErrInvalidNumber}
+		}
+	}
+
+	if d.LateFee != nil {
+		if _, err := decimal.NewFromString(d.LateFee.Percent); err != nil {
+			return &FieldError{Field: "LateFee.Percent", Err: ErrInvalidNumber}
+		}
+	}
+
+	if d.BankDetails != nil {
+		if err := d.BankDetails.validate(); err != nil {
+			return err
+		}
+	}
+
+	if d.Options != nil && d.Options.PaymentQR != nil {
+		return d.validatePaymentQR()
+	}
+
+	return nil
+}
+
+// strictPercents return true if out of range taxes and discounts percents are
+// rejected, see Options.ClampPercents
+func (d *Document) strictPercents() bool {
+	return d.Options == nil || !d.Options.ClampPercents
+}
+
+// validateItems check items values consistency
+func (d *Document) validateItems() error {
+	strict := d.strictPercents()
+	for index, item := range d.Items {
+		if item.unitCost().IsNegative() && d.Type != CreditNote {
+			return &ItemError{Index: index, Field: "UnitCost", Err: ErrNegativeUnitCost}
+		}
+
+		if item.quantity().IsNegative() && d.Type != CreditNote {
+			return &ItemError{Index: index, Field: "Quantity", Err: ErrNegativeQuantity}
+		}
+
+		if item.Tax != nil {
+			if err := item.Tax.validate(strict); err != nil {
+				return &ItemError{Index: index, Field: "Tax", Err: err}
+			}
+		}
+
+		for taxIndex, tax := range item.Taxes {
+			if err := tax.validate(strict); err != nil {
+				return &ItemError{Index: index, Field: fmt.Sprintf("Taxes[%d]", taxIndex), Err: err}
+			}
+		}
+
+		if item.discountExceedsTotal(!strict) {
+			return &ItemError{Index: index, Field: "Discount", Err: ErrDiscountExceedsTotal}
+		}
+
+		if item.Discount != nil {
+			if err := item.Discount.validate(strict); err != nil {
+				return &ItemError{Index: index, Field: "Discount", Err: err}
+			}
+		}
+	}
+
+	return nil
+}
+
+// itemsTotal return the sum of items totals, items discounts included
+func (d *Document) itemsTotal() decimal.Decimal {
+	total := decimal.NewFromFloat(0)
+	for _, item := range d.convertedItems(d.activeItems()) {
+		total = total.Add(item.totalWithoutTaxAndWithDiscount())
+	}
+
+	return total
+}
+
+// validateTaxIDs check company and customer VAT identification numbers format
+// with Options.StrictTaxIDs
+func (d *Document) validateTaxIDs() error {
+	if d.Options == nil || !d.Options.StrictTaxIDs {
+		return nil
+	}
+
+	if err := validateTaxID(d.Company.TaxID); err != nil {
+		return &FieldError{Field: "Company.TaxID", Err: err}
+	}
+
+	if err := validateTaxID(d.Customer.TaxID); err != nil {
+		return &FieldError{Field: "Customer.TaxID", Err: err}
+	}
+
+	return nil
+}
+
+// validate check options having a fixed set of values
+func (o *Options) validate() error {
+	if o == nil {
+		return nil
+	}
+
+	if !validOption(o.RoundingMode, []string{RoundingModeNone, RoundingModeHalfUp, RoundingModeBankers}) {
+		return &FieldError{Field: "Options.RoundingMode", Err: ErrInvalidOption}
+	}
+
+	if !validOption(o.NotesMode, []string{NotesModeClamp, NotesModeFlowAfterTotals, NotesModeSeparatePage}) {
+		return &FieldError{Field: "Options.NotesMode", Err: ErrInvalidOption}
+	}
+
+	if !validOption(o.NotesPosition, []string{NotesPositionBesideTotals, NotesPositionBelowTotals, NotesPositionAboveTotals}) {
+		return &FieldError{Field: "Options.NotesPosition", Err: ErrInvalidOption}
+	}
+
+	if !validOption(o.TableBorders, []string{TableBordersNone, TableBordersHorizontal, TableBordersFull}) {
+		return &FieldError{Field: "Options.TableBorders", Err: ErrInvalidOption}
+	}
+
+	return nil
+}
+
+// validOption return true if value is empty (default) or one of allowed values
+func validOption(value string, allowed []string) bool {
+	if len(value) == 0 {
+		return true
+	}
+
+	for _, option := range allowed {
+		if value == option {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,34 @@
+package generator
+
+import (
+	"github.com/jung-kurt/gofpdf"
+)
+
+// appendWatermark draw Options.Watermark diagonally across current page,
+// in large semi-transparent grey text. Called from header func so it is
+// drawn on every page before content.
+func (d *Document) appendWatermark(pdf *gofpdf.Fpdf) {
+	if len(d.Options.Watermark) == 0 {
+		return
+	}
+
+	text := d.Options.encodeString(d.Options.Watermark)
+	pageWidth, pageHeight := d.pageSize()
+	centerX := pageWidth / 2
+	centerY := pageHeight / 2
+
+	pdf.SetFont(d.Options.fontFamily(), "B", 96)
+	pdf.SetTextColor(d.Options.Theme.GreyTextColor[0], d.Options.Theme.GreyTextColor[1], d.Options.Theme.GreyTextColor[2])
+	pdf.SetAlpha(0.15, "Normal")
+
+	_, fontHeight := pdf.GetFontSize()
+	textWidth := pdf.GetStringWidth(text)
+
+	pdf.TransformBegin()
+	pdf.TransformRotate(45, centerX, centerY)
+	pdf.Text(centerX-textWidth/2, centerY+fontHeight/3, text)
+	pdf.TransformEnd()
+
+	pdf.SetAlpha(1, "Normal")
+	pdf.SetTextColor(d.Options.Theme.BaseTextColor[0], d.Options.Theme.BaseTextColor[1], d.Options.Theme.BaseTextColor[2])
+}
@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+)
+
+// XMPNamespace define the namespace used for invoice fields in xmp metadata
+const XMPNamespace string = "https://github.com/angelodlfrtr/go-invoice-generator/xmp/1.0/"
+
+// xmpMetadata serialize document key fields (type, ref, date, totals breakdown,
+// currency) as an xmp packet
+func (d *Document) xmpMetadata() []byte {
+	totals := d.computeTotals()
+	precision := int32(d.Options.CurrencyPrecision)
+
+	// Currency as ISO 4217 code when known
+	currency := strings.ToUpper(d.Options.CurrencyCode)
+	if len(currency) == 0 {
+		currency = strings.TrimSpace(d.Options.CurrencySymbol)
+	}
+
+	fields := [][2]string{
+		{"Type", d.Type},
+		{"Ref", d.Ref},
+		{"Date", d.date()},
+		{"TotalWithoutTax", totals.total.StringFixed(precision)},
+		{"TotalNet", totals.totalWithTax.Sub(totals.totalTax).StringFixed(precision)},
+		{"TotalTax", totals.totalTax.StringFixed(precision)},
+		{"TotalGross", totals.totalWithTax.StringFixed(precision)},
+		{"Total", totals.totalWithTax.StringFixed(precision)},
+		{"AmountDue", totals.amountDue.StringFixed(precision)},
+		{"Currency", currency},
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`<?xpacket begin="" id="W5M0MpCehiHzreSzNTczkc9d"?>` + "\n")
+	buf.WriteString(`<x:xmpmeta xmlns:x="adobe:ns:meta/">` + "\n")
+	buf.WriteString(`<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">` + "\n")
+	buf.WriteString(`<rdf:Description rdf:about="" xmlns:inv="` + XMPNamespace + `">` + "\n")
+
+	for _, field := range fields {
+		buf.WriteString("<inv:" + field[0] + ">")
+		xml.EscapeText(&buf, []byte(field[1]))
+		buf.WriteString("</inv:" + field[0] + ">\n")
+	}
+
+	buf.WriteString("</rdf:Description>\n")
+	buf.WriteString("</rdf:RDF>\n")
+	buf.WriteString("</x:xmpmeta>\n")
+	buf.WriteString(`<?xpacket end="w"?>`)
+
+	return buf.Bytes()
+}